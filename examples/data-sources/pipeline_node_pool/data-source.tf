@@ -0,0 +1,12 @@
+data "pipeline_node_pool" "shared" {
+  name        = "shared-ubuntu"
+  project_key = "platform"
+}
+
+resource "pipeline_node" "my-node" {
+  friendly_name       = "my-node"
+  project_id          = data.pipeline_node_pool.shared.project_id
+  node_pool_id        = data.pipeline_node_pool.shared.id
+  is_on_demand        = false
+  is_auto_initialized = false
+}
@@ -0,0 +1,9 @@
+data "pipeline_node_pools" "static" {
+  project_key      = "platform"
+  is_on_demand     = false
+  operating_system = "Ubuntu_20.04"
+}
+
+output "static_node_ids" {
+  value = flatten([for pool in data.pipeline_node_pools.static.node_pools : pool.nodes[*].id])
+}
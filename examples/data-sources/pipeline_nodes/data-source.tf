@@ -0,0 +1,11 @@
+data "pipeline_nodes" "static" {
+  project_key  = "platform"
+  is_on_demand = false
+}
+
+check "static_nodes_online" {
+  assert {
+    condition     = alltrue([for node in data.pipeline_nodes.static.nodes : node.status == "online"])
+    error_message = "Some static nodes are not online: ${join(", ", [for node in data.pipeline_nodes.static.nodes : node.friendly_name if node.status != "online"])}"
+  }
+}
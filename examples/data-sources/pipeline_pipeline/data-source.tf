@@ -0,0 +1,9 @@
+data "pipeline_pipeline" "build" {
+  name               = "my_build_pipeline"
+  pipeline_source_id = pipeline_source.my-pipeline-source.id
+  branch             = "main"
+}
+
+output "build_node_pools" {
+  value = distinct(data.pipeline_pipeline.build.steps[*].node_pool)
+}
@@ -0,0 +1,11 @@
+data "pipeline_pipelines" "synced" {
+  pipeline_source_id = pipeline_source.my-pipeline-source.id
+  branch             = "main"
+}
+
+check "pipelines_synced" {
+  assert {
+    condition     = length(setsubtract(["build", "deploy"], data.pipeline_pipelines.synced.pipelines[*].name)) == 0
+    error_message = "The pipeline source didn't sync every expected pipeline."
+  }
+}
@@ -1,3 +1,7 @@
 data "pipeline_project" "my-project" {
   name = "my-project"
 }
+
+data "pipeline_project" "my-project-by-key" {
+  key = "myproj"
+}
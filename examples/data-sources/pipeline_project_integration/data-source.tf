@@ -0,0 +1,13 @@
+data "pipeline_project_integration" "github" {
+  name        = "my-github-integration"
+  project_key = "myproj"
+}
+
+resource "pipeline_source" "my-pipeline-source" {
+  name                   = "my-pipeline-source"
+  project_id             = data.pipeline_project_integration.github.project_id
+  project_integration_id = data.pipeline_project_integration.github.id
+  repository_full_name   = "myrepo/docker-sample"
+  file_filter            = "pipelines.yml"
+  branch                 = "main"
+}
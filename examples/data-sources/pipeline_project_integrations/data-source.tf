@@ -0,0 +1,4 @@
+data "pipeline_project_integrations" "github" {
+  master_integration_name = "github"
+  project_key             = "myproj"
+}
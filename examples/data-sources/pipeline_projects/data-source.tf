@@ -0,0 +1,13 @@
+data "pipeline_projects" "all" {
+  key_regex = "^team-"
+}
+
+resource "pipeline_node_pool" "shared" {
+  for_each = { for project in data.pipeline_projects.all.projects : project.key => project }
+
+  name             = "${each.key}-shared"
+  project_id       = each.value.id
+  is_on_demand     = false
+  architecture     = "x86_64"
+  operating_system = "Ubuntu_20.04"
+}
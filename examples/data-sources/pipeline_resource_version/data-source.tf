@@ -0,0 +1,34 @@
+data "pipeline_resource_version" "app_image" {
+  resource_name      = "app_image"
+  pipeline_source_id = pipeline_source.my-pipeline-source.id
+}
+
+# deploy the image tag built by the pipeline
+resource "kubernetes_deployment" "app" {
+  metadata {
+    name = "app"
+  }
+
+  spec {
+    selector {
+      match_labels = {
+        app = "app"
+      }
+    }
+
+    template {
+      metadata {
+        labels = {
+          app = "app"
+        }
+      }
+
+      spec {
+        container {
+          name  = "app"
+          image = "${data.pipeline_resource_version.app_image.properties["imageName"]}:${data.pipeline_resource_version.app_image.properties["imageTag"]}"
+        }
+      }
+    }
+  }
+}
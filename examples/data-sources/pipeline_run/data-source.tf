@@ -0,0 +1,27 @@
+data "pipeline_run" "latest_main" {
+  pipeline_name           = "release"
+  pipeline_source_id      = pipeline_source.my-pipeline-source.id
+  branch                  = "main"
+  wait_for_terminal_state = true
+
+  timeouts {
+    read = "20m"
+  }
+}
+
+# only promote when the last run of the release pipeline on main succeeded
+resource "pipeline_run" "promote" {
+  pipeline_name = "promote"
+  branch        = "main"
+
+  environment_variables = {
+    COMMIT_SHA = data.pipeline_run.latest_main.commit_sha
+  }
+
+  lifecycle {
+    precondition {
+      condition     = data.pipeline_run.latest_main.status == "success"
+      error_message = "The last run of the release pipeline on main didn't succeed."
+    }
+  }
+}
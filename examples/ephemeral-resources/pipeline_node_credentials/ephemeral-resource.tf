@@ -0,0 +1,18 @@
+ephemeral "pipeline_node_credentials" "my-manual-node" {
+  node_id = pipeline_node.my-manual-node.id
+}
+
+# the init script is passed to the VM without being stored in the state or the plan
+resource "terraform_data" "bootstrap" {
+  triggers_replace = [aws_instance.build-agent.id]
+
+  connection {
+    type = "ssh"
+    user = "ubuntu"
+    host = aws_instance.build-agent.public_ip
+  }
+
+  provisioner "remote-exec" {
+    inline = [ephemeral.pipeline_node_credentials.my-manual-node.init_script]
+  }
+}
@@ -0,0 +1,5 @@
+resource "pipeline_default_node_pool" "my-project-default" {
+  project_key  = "myproj"
+  node_pool_id = pipeline_node_pool.my-node-pool.id
+  on_destroy   = "restore"
+}
@@ -2,8 +2,35 @@ resource "pipeline_node" "my-node" {
   friendly_name       = "my-node"
   project_id          = 0
   node_pool_id        = 0
-  is_on_demand        = true
+  is_on_demand        = false
   is_auto_initialized = true
   ip_address          = "10.0.0.1"
   is_swap_enabled     = true
+
+  # wait until the node is online before the resources depending on it are created
+  wait_for_initialization = true
+
+  timeouts {
+    create = "20m"
+  }
+}
+
+resource "pipeline_node" "my-manual-node" {
+  friendly_name       = "my-manual-node"
+  project_id          = 0
+  node_pool_id        = 0
+  is_on_demand        = false
+  is_auto_initialized = false
+
+  # change to reset the node and rotate its token, e.g. after the VM was compromised
+  reinitialize_triggers = {
+    rotated_at = "2024-01-15"
+  }
+}
+
+# bootstrap the VM with the init script of the node
+resource "aws_instance" "build-agent" {
+  ami           = "ami-0123456789abcdef0"
+  instance_type = "t3.large"
+  user_data     = pipeline_node.my-manual-node.init_script
 }
@@ -7,4 +7,44 @@ resource "pipeline_node_pool" "my-node-pool" {
   operating_system           = "Ubuntu_18.04"
   node_idle_interval_in_mins = 20
   environments               = ["DEV"]
+  delete_behavior            = "drain"
+
+  timeouts {
+    delete = "1h"
+  }
+}
+
+resource "pipeline_node_pool" "my-dynamic-node-pool" {
+  name                       = "my-dynamic-node-pool"
+  project_key                = "myproj"
+  is_on_demand               = true
+  architecture               = "x86_64"
+  operating_system           = "Ubuntu_20.04"
+  node_idle_interval_in_mins = 20
+
+  aws {
+    integration_id     = 0
+    instance_type      = "c5.xlarge"
+    region             = "us-east-1"
+    subnet_id          = "subnet-0123456789abcdef0"
+    security_group_ids = ["sg-0123456789abcdef0"]
+    disk_size_in_gb    = 100
+    min_nodes          = 0
+    max_nodes          = 10
+    tags = {
+      team = "platform"
+    }
+  }
+
+  runtime {
+    type             = "container"
+    image_registry   = "releases-docker.jfrog.io"
+    image_repository = "jfrog/pipelines-u20node"
+    language_versions = {
+      node = "18"
+    }
+    environment_variables = {
+      CI = "true"
+    }
+  }
 }
@@ -19,4 +19,10 @@ resource "pipeline_project_integration" "my-project-integration" {
     label = "label-2"
     value = "value-2"
   }
+
+  secret_form_json_values {
+    label    = "token"
+    value_wo = var.token
+  }
+  secret_version = 1
 }
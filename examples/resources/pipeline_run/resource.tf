@@ -0,0 +1,21 @@
+resource "pipeline_run" "bootstrap" {
+  pipeline_name       = "bootstrap_environment"
+  pipeline_source_id  = pipeline_source.my-pipeline-source.id
+  branch              = "main"
+  step_name           = "create_database"
+  wait_for_completion = true
+
+  environment_variables = {
+    ENVIRONMENT = "staging"
+    CLUSTER     = aws_eks_cluster.staging.name
+  }
+
+  # run the pipeline again when the cluster is replaced
+  triggers = {
+    cluster_id = aws_eks_cluster.staging.id
+  }
+
+  timeouts {
+    create = "30m"
+  }
+}
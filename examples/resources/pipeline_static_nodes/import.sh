@@ -0,0 +1,2 @@
+# imports all the nodes of the node pool
+terraform import pipeline_static_nodes.bare-metal 12
@@ -0,0 +1,26 @@
+locals {
+  build_agents = {
+    "myproj-agent-01" = "10.0.1.11"
+    "myproj-agent-02" = "10.0.1.12"
+    "myproj-agent-03" = "10.0.1.13"
+  }
+}
+
+resource "pipeline_static_nodes" "bare-metal" {
+  project_key  = "myproj"
+  node_pool_id = pipeline_node_pool.my-node-pool.id
+  concurrency  = 10
+
+  dynamic "node" {
+    for_each = local.build_agents
+
+    content {
+      friendly_name = node.key
+      ip_address    = node.value
+    }
+  }
+}
+
+output "build_agent_ids" {
+  value = pipeline_static_nodes.bare-metal.node_ids
+}
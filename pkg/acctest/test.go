@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/jfrog/terraform-provider-pipeline/pkg/pipeline"
@@ -18,6 +19,9 @@ import (
 var Provider *schema.Provider
 var ProviderFactories map[string]func() (*schema.Provider, error)
 
+// ProtoV5ProviderFactories serves the muxed provider, for the tests of the types only served by the framework provider
+var ProtoV5ProviderFactories map[string]func() (tfprotov5.ProviderServer, error)
+
 // testAccProviderConfigure ensures Provider is only configured once
 //
 // The PreCheck(t) function is invoked for every test and this prevents
@@ -32,6 +36,16 @@ func init() {
 	ProviderFactories = map[string]func() (*schema.Provider, error){
 		"pipeline": func() (*schema.Provider, error) { return pipeline.Provider(), nil },
 	}
+
+	ProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+		"pipeline": func() (tfprotov5.ProviderServer, error) {
+			providerServer, err := pipeline.MuxProviderServer(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			return providerServer(), nil
+		},
+	}
 }
 
 // PreCheck This function should be present in every acceptance test.
@@ -158,3 +172,33 @@ func DeleteProjectIntegration(t *testing.T, id int) {
 		t.Fatal(err)
 	}
 }
+
+func GetNodePool(t *testing.T, id int) pipeline.NodePool {
+	restyClient := GetTestResty(t)
+
+	nodePools := []pipeline.NodePool{}
+	_, err := restyClient.R().
+		SetResult(&nodePools).
+		Get(fmt.Sprintf("/pipelines/api/v1/nodePools?nodePoolIds=%d", id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodePools) == 0 {
+		t.Fatalf("node pool %d not found", id)
+	}
+
+	return nodePools[0]
+}
+
+func SetNodePoolDefault(t *testing.T, id int, isDefault bool) {
+	restyClient := GetTestResty(t)
+
+	nodePool := GetNodePool(t, id)
+	nodePool.IsDefault = &isDefault
+	_, err := restyClient.R().
+		SetBody(nodePool).
+		Put(fmt.Sprintf("/pipelines/api/v1/nodePools/%d", id))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,216 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+var nodePoolNodesSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Computed: true,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the node.",
+			},
+			"friendly_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the node.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the node, as reported by the server.",
+			},
+		},
+	},
+	Description: "The nodes of the node pool.",
+}
+
+// computedSchema returns a copy of a resource schema where all the attributes are computed, to reuse it in a data source
+func computedSchema(resourceSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	computed := map[string]*schema.Schema{}
+	for key, s := range resourceSchema {
+		elem := s.Elem
+		if resource, ok := s.Elem.(*schema.Resource); ok {
+			elem = &schema.Resource{Schema: computedSchema(resource.Schema)}
+		}
+
+		computed[key] = &schema.Schema{
+			Type:        s.Type,
+			Computed:    true,
+			Sensitive:   s.Sensitive,
+			Elem:        elem,
+			Description: s.Description,
+		}
+	}
+	return computed
+}
+
+func getNodePoolNodes(client *resty.Client, nodePoolIds ...int) ([]Node, error) {
+	nodes := []Node{}
+	_, err := client.R().
+		SetQueryParam("nodePoolIds", joinIds(nodePoolIds)).
+		SetResult(&nodes).
+		Get(nodesUrl)
+	return nodes, err
+}
+
+func packNodePoolNodes(nodes []Node) []interface{} {
+	packed := []interface{}{}
+	for _, node := range nodes {
+		packed = append(packed, map[string]interface{}{
+			"id":            node.ID,
+			"friendly_name": node.FriendlyName,
+			"status":        node.Status,
+		})
+	}
+	return packed
+}
+
+func nodePoolDataSource() *schema.Resource {
+	resourceSchema := computedSchema(pipelineNodePoolResource().Schema)
+	delete(resourceSchema, "delete_behavior")
+
+	nodePoolSchema := util.MergeMaps(
+		resourceSchema,
+		map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The Id of the node pool.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the node pool.",
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Key of the project of the node pool. Used with `name` to narrow down the lookup.",
+			},
+			"is_default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether this is the default node pool of its project.",
+			},
+			"nodes": nodePoolNodesSchema,
+		},
+	)
+
+	return &schema.Resource{
+		ReadContext: dataSourceNodePoolRead,
+		Schema:      nodePoolSchema,
+		Description: "Gets a Pipelines node pool and its nodes, such as a shared node pool managed by another team.",
+	}
+}
+
+func dataSourceNodePoolRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	var project *Project
+	if projectKey := d.GetString("project_key", false); projectKey != "" {
+		var err error
+		project, err = findProjectByKey(client, projectKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var nodePool NodePool
+	if id := d.GetString("id", false); id != "" {
+		nodePoolId, err := strconv.Atoi(id)
+		if err != nil {
+			return diag.Errorf("expected a numeric node pool id, got '%s'", id)
+		}
+		found, err := getNodePool(client, nodePoolId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if found == nil {
+			return diag.Errorf("no node pool found with id %d", nodePoolId)
+		}
+		nodePool = *found
+	} else {
+		name := d.GetString("name", false)
+		req := client.R()
+		if project != nil {
+			req.SetQueryParam("projectIds", strconv.Itoa(project.Id))
+		}
+
+		var nodePools []NodePool
+		_, err := req.SetResult(&nodePools).Get(nodePoolsUrl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var matches []NodePool
+		for _, nodePool := range nodePools {
+			if nodePool.Name == name {
+				matches = append(matches, nodePool)
+			}
+		}
+		tflog.Debug(ctx, fmt.Sprintf("found %d node pools with name '%s'", len(matches), name))
+
+		if len(matches) == 0 {
+			return diag.Errorf("no node pool found with name '%s'", name)
+		}
+		if len(matches) > 1 {
+			return diag.Errorf("%d node pools found with name '%s', set project_key to narrow down the lookup", len(matches), name)
+		}
+		nodePool = matches[0]
+	}
+
+	if project == nil && nodePool.ProjectId != 0 {
+		var err error
+		project, err = findProjectById(client, nodePool.ProjectId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	nodes, err := getNodePoolNodes(client, nodePool.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(strconv.Itoa(nodePool.ID))
+	if diags := packNodePool(data, nodePool); diags.HasError() {
+		return diags
+	}
+
+	projectKey := ""
+	if project != nil {
+		projectKey = project.Key
+	}
+
+	setValue := util.MkLens(data)
+	errors := setValue("project_key", projectKey)
+	errors = append(errors, setValue("is_default", nodePool.IsDefault != nil && *nodePool.IsDefault)...)
+	errors = append(errors, setValue("nodes", packNodePoolNodes(nodes))...)
+	if len(errors) > 0 {
+		return diag.Errorf("failed to pack node pool %q", errors)
+	}
+
+	return nil
+}
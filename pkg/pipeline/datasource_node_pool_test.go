@@ -0,0 +1,72 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestAccDatasourceNodePool(t *testing.T) {
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	_, fqrn, name := test.MkNames("node-pool", "pipeline_node_pool")
+	dataSourceName := fmt.Sprintf("data.%s", fqrn)
+	listDataSourceName := fmt.Sprintf("data.pipeline_node_pools.%s", name)
+
+	config := util.ExecuteTemplate("TestDatasourceNodePoolConfig", `
+		resource "pipeline_node_pool" "{{ .name }}" {
+			name             = "{{ .name }}"
+			project_key      = "{{ .projectKey }}"
+			number_of_nodes  = 1
+			is_on_demand     = false
+			architecture     = "x86_64"
+			operating_system = "Ubuntu_20.04"
+		}
+
+		data "pipeline_node_pool" "{{ .name }}" {
+			name        = pipeline_node_pool.{{ .name }}.name
+			project_key = "{{ .projectKey }}"
+		}
+
+		data "pipeline_node_pools" "{{ .name }}" {
+			project_key  = "{{ .projectKey }}"
+			is_on_demand = false
+
+			depends_on = [pipeline_node_pool.{{ .name }}]
+		}
+	`, map[string]interface{}{
+		"name":       name,
+		"projectKey": projectKey,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "pipeline_node_pool."+name, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "operating_system", "Ubuntu_20.04"),
+					resource.TestCheckResourceAttr(dataSourceName, "is_on_demand", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.#", "0"),
+					resource.TestCheckResourceAttr(listDataSourceName, "node_pools.#", "1"),
+					resource.TestCheckResourceAttr(listDataSourceName, "node_pools.0.name", name),
+					resource.TestCheckResourceAttr(listDataSourceName, "node_pools.0.project_key", projectKey),
+					resource.TestCheckResourceAttr(listDataSourceName, "node_pools.0.operating_system", "Ubuntu_20.04"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func nodePoolsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceNodePoolsRead,
+
+		Schema: map[string]*schema.Schema{
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return node pools of this project.",
+			},
+			"is_on_demand": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return dynamic node pools when `true`, or static node pools when `false`. Return both when not set.",
+			},
+			"operating_system": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return node pools with this operating system, e.g. `Ubuntu_20.04`.",
+			},
+			"node_pools": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: nodePoolsElementSchema(),
+				},
+				Description: "The matching node pools.",
+			},
+		},
+
+		Description: "Gets the node pools matching the filters, with their nodes.",
+	}
+}
+
+// nodePoolsElementSchema returns the schema of a node pool in the list, with the same attributes as the pipeline_node_pool data source
+func nodePoolsElementSchema() map[string]*schema.Schema {
+	resourceSchema := computedSchema(pipelineNodePoolResource().Schema)
+	delete(resourceSchema, "delete_behavior")
+
+	return util.MergeMaps(
+		resourceSchema,
+		map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The Id of the node pool.",
+			},
+			"is_default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether this is the default node pool of its project.",
+			},
+			"nodes": nodePoolNodesSchema,
+		},
+	)
+}
+
+// packNodePoolValues packs a node pool as an element of the node_pools list, reusing the packing of the resource attributes
+func packNodePoolValues(nodePool NodePool, projectKey string, nodes []Node) map[string]interface{} {
+	values := map[string]interface{}{
+		"id":                         nodePool.ID,
+		"name":                       nodePool.Name,
+		"project_id":                 nodePool.ProjectId,
+		"project_key":                projectKey,
+		"number_of_nodes":            nodePool.NumberOfNodes,
+		"is_on_demand":               nodePool.IsOnDemand,
+		"architecture":               nodePool.Architecture,
+		"operating_system":           nodePool.OperatingSystem,
+		"node_idle_interval_in_mins": nodePool.NodeIdleIntervalInMins,
+		"environments":               nodePool.Environments,
+		"runtime":                    packNodePoolRuntime(nodePool.Runtime),
+		"is_default":                 nodePool.IsDefault != nil && *nodePool.IsDefault,
+		"nodes":                      packNodePoolNodes(nodes),
+	}
+
+	setValue := func(key string, value interface{}) []error {
+		values[key] = value
+		return nil
+	}
+	packNodePoolProviderSettings(setValue, nodePool.ProviderSettings)
+
+	return values
+}
+
+func dataSourceNodePoolsRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	projectKey := d.GetString("project_key", false)
+	operatingSystem := d.GetString("operating_system", false)
+	// GetOk can't tell false from unset
+	isOnDemand := data.GetRawConfig().GetAttr("is_on_demand")
+
+	req := client.R()
+	if projectKey != "" {
+		project, err := findProjectByKey(client, projectKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		req.SetQueryParam("projectIds", strconv.Itoa(project.Id))
+	}
+
+	var nodePools []NodePool
+	_, err := req.SetResult(&nodePools).Get(nodePoolsUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var matches []NodePool
+	for _, nodePool := range nodePools {
+		if operatingSystem != "" && nodePool.OperatingSystem != operatingSystem {
+			continue
+		}
+		if !isOnDemand.IsNull() && nodePool.IsOnDemand != isOnDemand.True() {
+			continue
+		}
+		matches = append(matches, nodePool)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("found %d node pools", len(matches)))
+
+	nodesByPool := map[int][]Node{}
+	if len(matches) > 0 {
+		nodePoolIds := make([]int, len(matches))
+		for i, nodePool := range matches {
+			nodePoolIds[i] = nodePool.ID
+		}
+
+		nodes, err := getNodePoolNodes(client, nodePoolIds...)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, node := range nodes {
+			nodesByPool[node.NodePoolId] = append(nodesByPool[node.NodePoolId], node)
+		}
+	}
+
+	projectKeys := map[int]string{}
+	var packed []interface{}
+	for _, nodePool := range matches {
+		key := projectKey
+		if key == "" && nodePool.ProjectId != 0 {
+			var ok bool
+			if key, ok = projectKeys[nodePool.ProjectId]; !ok {
+				project, err := findProjectById(client, nodePool.ProjectId)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				key = project.Key
+				projectKeys[nodePool.ProjectId] = key
+			}
+		}
+		packed = append(packed, packNodePoolValues(nodePool, key, nodesByPool[nodePool.ID]))
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(fmt.Sprintf("%s/%s/%s", projectKey, operatingSystem, isOnDemand.GoString()))))
+	if err := data.Set("node_pools", packed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
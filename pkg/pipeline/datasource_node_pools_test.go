@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"testing"
+)
+
+func TestPackNodePoolValues(t *testing.T) {
+	isDefault := true
+	nodePool := NodePool{
+		ID:              3,
+		Name:            "aws-pool",
+		ProjectId:       1,
+		IsOnDemand:      true,
+		Architecture:    "x86_64",
+		OperatingSystem: "Ubuntu_20.04",
+		IsDefault:       &isDefault,
+		ProviderSettings: &NodePoolProviderSettings{
+			Provider:      "AWS",
+			IntegrationId: 12,
+			InstanceType:  "c5.xlarge",
+			Region:        "us-east-1",
+		},
+		Runtime: &NodePoolRuntime{
+			Type:             "image",
+			ImageRegistry:    "releases-docker.jfrog.io",
+			LanguageVersions: map[string]string{"node": "18"},
+		},
+	}
+
+	data := nodePoolsDataSource().TestResourceData()
+	data.SetId("test")
+	packed := packNodePoolValues(nodePool, "myproj", []Node{{ID: 9, FriendlyName: "node-1"}})
+	if err := data.Set("node_pools", []interface{}{packed}); err != nil {
+		t.Fatalf("expected the element to match the schema, got %s", err)
+	}
+
+	for key, expected := range map[string]string{
+		"node_pools.0.id":                               "3",
+		"node_pools.0.project_key":                      "myproj",
+		"node_pools.0.is_default":                       "true",
+		"node_pools.0.aws.0.instance_type":              "c5.xlarge",
+		"node_pools.0.aws.0.integration_id":             "12",
+		"node_pools.0.runtime.0.image_registry":         "releases-docker.jfrog.io",
+		"node_pools.0.runtime.0.language_versions.node": "18",
+		"node_pools.0.nodes.0.friendly_name":            "node-1",
+	} {
+		if value := data.State().Attributes[key]; value != expected {
+			t.Errorf("expected %s to be '%s', got '%s'", key, expected, value)
+		}
+	}
+	if gcp := data.Get("node_pools.0.gcp").([]interface{}); len(gcp) != 0 {
+		t.Errorf("expected no gcp block, got %v", gcp)
+	}
+}
@@ -0,0 +1,161 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func nodesDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceNodesRead,
+
+		Schema: map[string]*schema.Schema{
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return nodes of this project.",
+			},
+			"node_pool_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Only return nodes of this node pool.",
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{nodeStatusInitializing, nodeStatusInitialized, nodeStatusOnline, nodeStatusOffline, nodeStatusFailed}, false),
+				Description:  "Only return nodes with this status: `initializing`, `initialized`, `online`, `offline` or `failed`.",
+			},
+			"is_on_demand": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return dynamic nodes when `true`, or static nodes when `false`. Return both when not set.",
+			},
+			"nodes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the node.",
+						},
+						"friendly_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the node.",
+						},
+						"project_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the project of the node.",
+						},
+						"node_pool_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the node pool of the node.",
+						},
+						"ip_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Address of the node.",
+						},
+						"is_on_demand": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the node is provisioned dynamically.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the node.",
+						},
+						"last_heartbeat_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Time of the last heartbeat of the node agent.",
+						},
+						"agent_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Version of the node agent.",
+						},
+					},
+				},
+				Description: "The matching nodes.",
+			},
+		},
+
+		Description: "Gets the nodes matching the filters, e.g. to check the health of static nodes.",
+	}
+}
+
+func dataSourceNodesRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	projectKey := d.GetString("project_key", false)
+	nodePoolId := d.GetInt("node_pool_id", false)
+	status := d.GetString("status", false)
+	// GetOk can't tell false from unset
+	isOnDemand := data.GetRawConfig().GetAttr("is_on_demand")
+
+	req := client.R()
+	if projectKey != "" {
+		project, err := findProjectByKey(client, projectKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		req.SetQueryParam("projectIds", strconv.Itoa(project.Id))
+	}
+	if nodePoolId != 0 {
+		req.SetQueryParam("nodePoolIds", strconv.Itoa(nodePoolId))
+	}
+
+	var nodes []Node
+	_, err := req.SetResult(&nodes).Get(nodesUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var packed []interface{}
+	for _, node := range nodes {
+		if status != "" && node.Status != status {
+			continue
+		}
+		if !isOnDemand.IsNull() && node.IsOnDemand != isOnDemand.True() {
+			continue
+		}
+
+		packed = append(packed, map[string]interface{}{
+			"id":                node.ID,
+			"friendly_name":     node.FriendlyName,
+			"project_id":        node.ProjectId,
+			"node_pool_id":      node.NodePoolId,
+			"ip_address":        node.IPAddress,
+			"is_on_demand":      node.IsOnDemand,
+			"status":            node.Status,
+			"last_heartbeat_at": node.LastHeartbeatAt,
+			"agent_version":     node.AgentVersion,
+		})
+	}
+	tflog.Debug(ctx, fmt.Sprintf("found %d nodes", len(packed)))
+
+	data.SetId(strconv.Itoa(schema.HashString(fmt.Sprintf("%s/%d/%s/%s", projectKey, nodePoolId, status, isOnDemand.GoString()))))
+	if err := data.Set("nodes", packed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
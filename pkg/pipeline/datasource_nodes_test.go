@@ -0,0 +1,69 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestAccDatasourceNodes(t *testing.T) {
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	_, fqrn, name := test.MkNames("nodes", "pipeline_nodes")
+	dataSourceName := fmt.Sprintf("data.%s", fqrn)
+
+	config := util.ExecuteTemplate("TestDatasourceNodesConfig", `
+		resource "pipeline_node_pool" "{{ .name }}" {
+			name             = "{{ .name }}"
+			project_key      = "{{ .projectKey }}"
+			number_of_nodes  = 1
+			is_on_demand     = false
+			architecture     = "x86_64"
+			operating_system = "Ubuntu_20.04"
+		}
+
+		resource "pipeline_node" "{{ .name }}" {
+			friendly_name       = "{{ .name }}"
+			project_key         = "{{ .projectKey }}"
+			node_pool_id        = pipeline_node_pool.{{ .name }}.id
+			is_on_demand        = false
+			is_auto_initialized = false
+		}
+
+		data "pipeline_nodes" "{{ .name }}" {
+			node_pool_id = pipeline_node_pool.{{ .name }}.id
+			is_on_demand = false
+
+			depends_on = [pipeline_node.{{ .name }}]
+		}
+	`, map[string]interface{}{
+		"name":       name,
+		"projectKey": projectKey,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "nodes.0.id", "pipeline_node."+name, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.0.friendly_name", name),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,288 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+type Pipeline struct {
+	Name                 string `json:"name"`
+	ProjectId            int    `json:"projectId"`
+	PipelineSourceId     int    `json:"pipelineSourceId"`
+	PipelineSourceBranch string `json:"pipelineSourceBranch"`
+	LatestRunId          int    `json:"latestRunId"`
+	ID                   int    `json:"id"`
+}
+
+type PipelineStepIntegration struct {
+	Name string `json:"name"`
+}
+
+type PipelineStepInput struct {
+	Name string `json:"name"`
+}
+
+type PipelineStep struct {
+	Name              string `json:"name"`
+	PipelineId        int    `json:"pipelineId"`
+	Type              string `json:"type"`
+	ConfigPropertyBag struct {
+		NodePool     string                    `json:"nodePool"`
+		Integrations []PipelineStepIntegration `json:"integrations"`
+		InputSteps   []PipelineStepInput       `json:"inputSteps"`
+	} `json:"configPropertyBag"`
+	ID int `json:"id"`
+}
+
+type RunStaticPropertyBag struct {
+	TriggeredByUserName string `json:"triggeredByUserName"`
+	CommitSha           string `json:"commitSha"`
+}
+
+type Run struct {
+	RunNumber         int                  `json:"runNumber"`
+	PipelineId        int                  `json:"pipelineId"`
+	StatusCode        int                  `json:"statusCode"`
+	StartedAt         string               `json:"startedAt,omitempty"`
+	EndedAt           string               `json:"endedAt,omitempty"`
+	DurationSeconds   int                  `json:"durationSeconds,omitempty"`
+	StaticPropertyBag RunStaticPropertyBag `json:"staticPropertyBag"`
+	ID                int                  `json:"id"`
+}
+
+const (
+	pipelinesUrl     = "pipelines/api/v1/pipelines"
+	pipelineStepsUrl = "pipelines/api/v1/pipelineSteps"
+	runsUrl          = "pipelines/api/v1/runs"
+)
+
+// getPipelineStepsAndRuns returns the declared steps and the latest run of each pipeline, keyed by pipeline id
+func getPipelineStepsAndRuns(client *resty.Client, pipelines []Pipeline) (map[int][]PipelineStep, map[int]Run, error) {
+	stepsByPipeline := map[int][]PipelineStep{}
+	latestRuns := map[int]Run{}
+	if len(pipelines) == 0 {
+		return stepsByPipeline, latestRuns, nil
+	}
+
+	var pipelineIds, runIds []int
+	for _, pipeline := range pipelines {
+		pipelineIds = append(pipelineIds, pipeline.ID)
+		if pipeline.LatestRunId != 0 {
+			runIds = append(runIds, pipeline.LatestRunId)
+		}
+	}
+
+	var steps []PipelineStep
+	_, err := client.R().
+		SetQueryParam("pipelineIds", joinIds(pipelineIds)).
+		SetResult(&steps).
+		Get(pipelineStepsUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, step := range steps {
+		stepsByPipeline[step.PipelineId] = append(stepsByPipeline[step.PipelineId], step)
+	}
+
+	if len(runIds) > 0 {
+		var runs []Run
+		_, err = client.R().
+			SetQueryParam("runIds", joinIds(runIds)).
+			SetResult(&runs).
+			Get(runsUrl)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, run := range runs {
+			latestRuns[run.PipelineId] = run
+		}
+	}
+
+	return stepsByPipeline, latestRuns, nil
+}
+
+// findPipeline returns the pipeline with this name, narrowed down by pipeline source and branch when set
+func findPipeline(client *resty.Client, name string, pipelineSourceId int, branch string) (*Pipeline, error) {
+	req := client.R().SetQueryParam("names", name)
+	if pipelineSourceId != 0 {
+		req.SetQueryParam("pipelineSourceIds", strconv.Itoa(pipelineSourceId))
+	}
+	if branch != "" {
+		req.SetQueryParam("pipelineSourceBranches", branch)
+	}
+
+	var pipelines []Pipeline
+	_, err := req.SetResult(&pipelines).Get(pipelinesUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pipelines) == 0 {
+		return nil, fmt.Errorf("no pipeline found with name '%s'", name)
+	}
+	if len(pipelines) > 1 {
+		return nil, fmt.Errorf("%d pipelines found with name '%s', set pipeline_source_id or branch to narrow down the lookup", len(pipelines), name)
+	}
+	return &pipelines[0], nil
+}
+
+var pipelineStepsSchema = &schema.Schema{
+	Type:     schema.TypeList,
+	Computed: true,
+	Elem: &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the step.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the step.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the step, e.g. `Bash`.",
+			},
+			"node_pool": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the node pool of the step. Empty when the step runs on the default node pool.",
+			},
+			"integrations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The names of the integrations of the step.",
+			},
+		},
+	},
+	Description: "The steps declared in the pipeline.",
+}
+
+func packPipelineSteps(steps []PipelineStep) []interface{} {
+	packed := []interface{}{}
+	for _, step := range steps {
+		integrations := []string{}
+		for _, integration := range step.ConfigPropertyBag.Integrations {
+			integrations = append(integrations, integration.Name)
+		}
+
+		packed = append(packed, map[string]interface{}{
+			"id":           step.ID,
+			"name":         step.Name,
+			"type":         step.Type,
+			"node_pool":    step.ConfigPropertyBag.NodePool,
+			"integrations": integrations,
+		})
+	}
+	return packed
+}
+
+// latestRunValues returns the number and status of the latest run, 0 and an empty status if the pipeline never ran
+func latestRunValues(run Run, ok bool) (int, string) {
+	if !ok {
+		return 0, ""
+	}
+	return run.RunNumber, statusName(run.StatusCode)
+}
+
+func pipelineDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePipelineRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the pipeline.",
+			},
+			"pipeline_source_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Id of the pipeline source the pipeline is synced from. Narrows down the lookup.",
+			},
+			"branch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The branch of the pipeline source, for multi-branch pipeline sources. Narrows down the lookup.",
+			},
+			"project_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the project of the pipeline.",
+			},
+			"latest_run_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the latest run. 0 if the pipeline never ran.",
+			},
+			"latest_run_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of the latest run. 0 if the pipeline never ran.",
+			},
+			"latest_run_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the latest run, e.g. `success`, `failure` or `processing`. Empty if the pipeline never ran.",
+			},
+			"steps": pipelineStepsSchema,
+		},
+
+		Description: "Gets a pipeline synced from a pipeline source, with its steps and latest run.",
+	}
+}
+
+func dataSourcePipelineRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	pipeline, err := findPipeline(client, d.GetString("name", false), d.GetInt("pipeline_source_id", false), d.GetString("branch", false))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("found pipeline %d", pipeline.ID))
+
+	stepsByPipeline, latestRuns, err := getPipelineStepsAndRuns(client, []Pipeline{*pipeline})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	latestRun, ok := latestRuns[pipeline.ID]
+	runNumber, runStatus := latestRunValues(latestRun, ok)
+
+	data.SetId(strconv.Itoa(pipeline.ID))
+
+	setValue := util.MkLens(data)
+	errors := setValue("name", pipeline.Name)
+	errors = append(errors, setValue("pipeline_source_id", pipeline.PipelineSourceId)...)
+	errors = append(errors, setValue("branch", pipeline.PipelineSourceBranch)...)
+	errors = append(errors, setValue("project_id", pipeline.ProjectId)...)
+	errors = append(errors, setValue("latest_run_id", pipeline.LatestRunId)...)
+	errors = append(errors, setValue("latest_run_number", runNumber)...)
+	errors = append(errors, setValue("latest_run_status", runStatus)...)
+	errors = append(errors, setValue("steps", packPipelineSteps(stepsByPipeline[pipeline.ID]))...)
+	if len(errors) > 0 {
+		return diag.Errorf("failed to pack pipeline %q", errors)
+	}
+
+	return nil
+}
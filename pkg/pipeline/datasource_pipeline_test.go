@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"testing"
+)
+
+func TestPackPipelineSteps(t *testing.T) {
+	step := PipelineStep{Name: "build", PipelineId: 1, Type: "Bash", ID: 2}
+	step.ConfigPropertyBag.NodePool = "ubuntu"
+	step.ConfigPropertyBag.Integrations = []PipelineStepIntegration{{Name: "github"}, {Name: "artifactory"}}
+
+	packed := packPipelineSteps([]PipelineStep{step})
+	if len(packed) != 1 {
+		t.Fatalf("expected 1 step, got %d", len(packed))
+	}
+
+	values := packed[0].(map[string]interface{})
+	if values["node_pool"] != "ubuntu" {
+		t.Errorf("expected node pool ubuntu, got %v", values["node_pool"])
+	}
+	if integrations := values["integrations"].([]string); len(integrations) != 2 || integrations[1] != "artifactory" {
+		t.Errorf("expected integrations github and artifactory, got %v", integrations)
+	}
+}
+
+func TestLatestRunValues(t *testing.T) {
+	if number, status := latestRunValues(Run{}, false); number != 0 || status != "" {
+		t.Errorf("expected no run, got %d %s", number, status)
+	}
+	if number, status := latestRunValues(Run{RunNumber: 12, StatusCode: statusSuccess}, true); number != 12 || status != "success" {
+		t.Errorf("expected run 12 success, got %d %s", number, status)
+	}
+	if _, status := latestRunValues(Run{RunNumber: 1, StatusCode: 4999}, true); status != "4999" {
+		t.Errorf("expected the unknown status code, got %s", status)
+	}
+}
+
+func TestFindEntryStep(t *testing.T) {
+	build := PipelineStep{Name: "build", ID: 1}
+	test := PipelineStep{Name: "test", ID: 2}
+	test.ConfigPropertyBag.InputSteps = []PipelineStepInput{{Name: "build"}}
+	lint := PipelineStep{Name: "lint", ID: 3}
+
+	// the entry step isn't the first step returned by the API
+	step, err := findEntryStep([]PipelineStep{test, build})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if step.Name != "build" {
+		t.Errorf("expected step build, got %s", step.Name)
+	}
+
+	if _, err := findEntryStep([]PipelineStep{build, test, lint}); err == nil {
+		t.Error("expected an error with several entry steps")
+	}
+}
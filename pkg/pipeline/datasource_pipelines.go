@@ -0,0 +1,182 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func pipelinesDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePipelinesRead,
+
+		Schema: map[string]*schema.Schema{
+			"pipeline_source_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Only return pipelines synced from this pipeline source.",
+			},
+			"branch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return pipelines of this branch.",
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return pipelines of this project.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return pipelines with a name matching this regular expression.",
+			},
+			"pipelines": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the pipeline.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the pipeline.",
+						},
+						"project_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the project of the pipeline.",
+						},
+						"pipeline_source_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the pipeline source the pipeline is synced from.",
+						},
+						"branch": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The branch of the pipeline source.",
+						},
+						"latest_run_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the latest run. 0 if the pipeline never ran.",
+						},
+						"latest_run_number": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Number of the latest run. 0 if the pipeline never ran.",
+						},
+						"latest_run_status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the latest run. Empty if the pipeline never ran.",
+						},
+						"steps": pipelineStepsSchema,
+					},
+				},
+				Description: "The matching pipelines.",
+			},
+		},
+
+		Description: "Gets the pipelines matching the filters, e.g. to check that every expected pipeline exists after syncing a pipeline source.",
+	}
+}
+
+func dataSourcePipelinesRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	pipelineSourceId := d.GetInt("pipeline_source_id", false)
+	branch := d.GetString("branch", false)
+	projectKey := d.GetString("project_key", false)
+	nameRegexString := d.GetString("name_regex", false)
+
+	// validated at plan time only when the value is known
+	var nameRegex *regexp.Regexp
+	if nameRegexString != "" {
+		var err error
+		nameRegex, err = regexp.Compile(nameRegexString)
+		if err != nil {
+			return diag.Errorf("invalid name_regex '%s': %s", nameRegexString, err)
+		}
+	}
+
+	req := client.R()
+	if pipelineSourceId != 0 {
+		req.SetQueryParam("pipelineSourceIds", strconv.Itoa(pipelineSourceId))
+	}
+	if branch != "" {
+		req.SetQueryParam("pipelineSourceBranches", branch)
+	}
+	if projectKey != "" {
+		project, err := findProjectByKey(client, projectKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		req.SetQueryParam("projectIds", strconv.Itoa(project.Id))
+	}
+
+	var pipelines []Pipeline
+	_, err := req.SetResult(&pipelines).Get(pipelinesUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if nameRegex != nil {
+		var matches []Pipeline
+		for _, pipeline := range pipelines {
+			if nameRegex.MatchString(pipeline.Name) {
+				matches = append(matches, pipeline)
+			}
+		}
+		pipelines = matches
+	}
+	tflog.Debug(ctx, fmt.Sprintf("found %d pipelines", len(pipelines)))
+
+	stepsByPipeline, latestRuns, err := getPipelineStepsAndRuns(client, pipelines)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var packed []interface{}
+	for _, pipeline := range pipelines {
+		latestRun, ok := latestRuns[pipeline.ID]
+		runNumber, runStatus := latestRunValues(latestRun, ok)
+
+		packed = append(packed, map[string]interface{}{
+			"id":                 pipeline.ID,
+			"name":               pipeline.Name,
+			"project_id":         pipeline.ProjectId,
+			"pipeline_source_id": pipeline.PipelineSourceId,
+			"branch":             pipeline.PipelineSourceBranch,
+			"latest_run_id":      pipeline.LatestRunId,
+			"latest_run_number":  runNumber,
+			"latest_run_status":  runStatus,
+			"steps":              packPipelineSteps(stepsByPipeline[pipeline.ID]),
+		})
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(fmt.Sprintf("%d/%s/%s/%s", pipelineSourceId, branch, projectKey, nameRegexString))))
+	if err := data.Set("pipelines", packed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDataSourcePipelinesRead_invalidNameRegex(t *testing.T) {
+	client, err := buildClient("http://127.0.0.1:0", "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := pipelinesDataSource().TestResourceData()
+	if err := data.Set("name_regex", "build-("); err != nil {
+		t.Fatal(err)
+	}
+
+	diags := dataSourcePipelinesRead(context.Background(), data, client.SetRetryCount(0))
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "invalid name_regex") {
+		t.Errorf("expected an error for the invalid regular expression, got %v", diags)
+	}
+}
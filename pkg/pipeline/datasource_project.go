@@ -2,57 +2,241 @@ package pipeline
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
 )
 
 type Project struct {
 	Name string `json:"name"`
+	Key  string `json:"key"`
 	Id   int    `json:"id"`
 }
 
+// AccessProject GET {{ host }}/access/api/v1/projects/{{prjKey}}
+type AccessProject struct {
+	Key         string `json:"project_key"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+}
+
+// AccessEnvironment GET {{ host }}/access/api/v1/projects/{{prjKey}}/environments
+type AccessEnvironment struct {
+	Name string `json:"name"`
+}
+
 const projectsUrl = "pipelines/api/v1/projects?names={projectName}"
+const allProjectsUrl = "pipelines/api/v1/projects"
+const accessProjectUrl = "access/api/v1/projects/{projectKey}"
+const accessProjectEnvironmentsUrl = "access/api/v1/projects/{projectKey}/environments"
 
 func projectDataSource() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceProjectRead,
 
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name", "key"},
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The Pipelines Id of the project.",
+			},
 			"name": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 				Description:  "The name of the project. Note: this is *not* the project key.",
 			},
+			"key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The key of the project.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The display name of the project.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the project.",
+			},
+			"environments": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Names of the environments of the project.",
+			},
+			"integrations_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of Pipelines integrations in the project.",
+			},
+			"pipeline_sources_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of pipeline sources in the project.",
+			},
+			"node_pools_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of node pools in the project.",
+			},
 		},
 
-		Description: "Gets the project that has an associated Pipelines object, such as an integration, pipeline source or node pool.",
+		Description: "Gets the project that has an associated Pipelines object, such as an integration, pipeline source or node pool. The project can be looked up by name, key or Pipelines Id.",
+	}
+}
+
+func dataSourceProjectRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	var project *Project
+	var err error
+	if id := d.GetString("id", false); id != "" {
+		projectId, err := strconv.Atoi(id)
+		if err != nil {
+			return diag.Errorf("invalid project id '%s': %s", id, err)
+		}
+		project, err = findProjectById(client, projectId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else if key := d.GetString("key", false); key != "" {
+		project, err = findProjectByKey(client, key)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		projectName := d.GetString("name", false)
+		var projects []Project
+		_, err := client.R().
+			SetResult(&projects).
+			SetPathParam("projectName", projectName).
+			Get(projectsUrl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if len(projects) == 0 {
+			return diag.Errorf("no project found with name '%s'", projectName)
+		}
+		if len(projects) > 1 {
+			return diag.Errorf("%d projects found with name '%s', look the project up by key or id instead", len(projects), projectName)
+		}
+		project = &projects[0]
 	}
+
+	var accessProject AccessProject
+	var environments []AccessEnvironment
+	if project.Key != "" {
+		_, err = client.R().
+			SetResult(&accessProject).
+			SetPathParam("projectKey", project.Key).
+			Get(accessProjectUrl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		_, err = client.R().
+			SetResult(&environments).
+			SetPathParam("projectKey", project.Key).
+			Get(accessProjectEnvironmentsUrl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	counts := map[string]int{}
+	for attr, url := range map[string]string{
+		"integrations_count":     projectIntegrationsUrl,
+		"pipeline_sources_count": pipelineSourcesUrl,
+		"node_pools_count":       nodePoolsUrl,
+	} {
+		var objects []json.RawMessage
+		_, err = client.R().
+			SetResult(&objects).
+			SetQueryParam("projectIds", strconv.Itoa(project.Id)).
+			Get(url)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		counts[attr] = len(objects)
+	}
+
+	return packProject(*project, accessProject, environments, counts, data)
+}
+
+func packProject(project Project, accessProject AccessProject, environments []AccessEnvironment, counts map[string]int, d *schema.ResourceData) diag.Diagnostics {
+	setValue := util.MkLens(d)
+
+	var environmentNames []string
+	for _, environment := range environments {
+		environmentNames = append(environmentNames, environment.Name)
+	}
+
+	d.SetId(strconv.Itoa(project.Id))
+	setValue("name", project.Name)
+	setValue("key", project.Key)
+	setValue("display_name", accessProject.DisplayName)
+	setValue("description", accessProject.Description)
+	setValue("environments", environmentNames)
+	var errs []error
+	for attr, count := range counts {
+		errs = append(errs, setValue(attr, count)...)
+	}
+
+	if len(errs) > 0 {
+		return diag.Errorf("failed to pack project %q", errs)
+	}
+
+	return nil
 }
 
-func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	projectName := d.Get("name").(string)
+func findProject(client *resty.Client, match func(Project) bool) (*Project, error) {
 	var projects []Project
-	_, err := m.(*resty.Client).R().
+	_, err := client.R().
 		SetResult(&projects).
-		SetPathParam("projectName", projectName).
-		Get(projectsUrl)
+		Get(allProjectsUrl)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 
-	if len(projects) == 0 {
-		return diag.Errorf("no project found with name '%s'", projectName)
+	for _, project := range projects {
+		if match(project) {
+			return &project, nil
+		}
 	}
+	return nil, nil
+}
 
-	return packProject(projects[0], d)
+func findProjectByKey(client *resty.Client, projectKey string) (*Project, error) {
+	project, err := findProject(client, func(p Project) bool { return p.Key == projectKey })
+	if err == nil && project == nil {
+		err = fmt.Errorf("no project found with key '%s'", projectKey)
+	}
+	return project, err
 }
 
-func packProject(project Project, d *schema.ResourceData) diag.Diagnostics {
-	d.SetId(strconv.Itoa(project.Id))
-	return nil
+func findProjectById(client *resty.Client, projectId int) (*Project, error) {
+	project, err := findProject(client, func(p Project) bool { return p.Id == projectId })
+	if err == nil && project == nil {
+		err = fmt.Errorf("no project found with id %d", projectId)
+	}
+	return project, err
 }
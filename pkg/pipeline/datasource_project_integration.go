@@ -0,0 +1,170 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func projectIntegrationDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceProjectIntegrationRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The Id of the project integration.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the project integration.",
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Key of the project of the integration. Used with `name` to narrow down the lookup.",
+			},
+			"project_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the project.",
+			},
+			"master_integration_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The Id of the master integration.",
+			},
+			"master_integration_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the master integration.",
+			},
+			"form_json_values": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The values of the integration, keyed by label. Sensitive values are redacted by the API and left out.",
+			},
+			"environments": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "In a project, an array of environment names in which this integration is available.",
+			},
+			"is_internal": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether this is an internal integration.",
+			},
+		},
+
+		Description: "Gets a Pipelines project integration, such as a GitHub integration shared between teams or created in the UI.",
+	}
+}
+
+func dataSourceProjectIntegrationRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	var project *Project
+	if projectKey := d.GetString("project_key", false); projectKey != "" {
+		var err error
+		project, err = findProjectByKey(client, projectKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var projectIntegration ProjectIntegration
+	if id := d.GetString("id", false); id != "" {
+		_, err := client.R().
+			SetResult(&projectIntegration).
+			Get(projectIntegrationsUrl + "/" + id)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		name := d.GetString("name", false)
+		req := client.R().SetQueryParam("names", name)
+		if project != nil {
+			req.SetQueryParam("projectIds", strconv.Itoa(project.Id))
+		}
+
+		var projectIntegrations []ProjectIntegration
+		_, err := req.SetResult(&projectIntegrations).Get(projectIntegrationsUrl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("found %d project integrations with name '%s'", len(projectIntegrations), name))
+
+		if len(projectIntegrations) == 0 {
+			return diag.Errorf("no project integration found with name '%s'", name)
+		}
+		if len(projectIntegrations) > 1 {
+			return diag.Errorf("%d project integrations found with name '%s', set project_key to narrow down the lookup", len(projectIntegrations), name)
+		}
+		projectIntegration = projectIntegrations[0]
+	}
+
+	if project == nil && projectIntegration.ProjectId != 0 {
+		var err error
+		project, err = findProjectById(client, projectIntegration.ProjectId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	projectKey := ""
+	if project != nil {
+		projectKey = project.Key
+	}
+
+	return packProjectIntegrationDataSource(data, projectIntegration, projectKey)
+}
+
+func packProjectIntegrationDataSource(d *schema.ResourceData, projectIntegration ProjectIntegration, projectKey string) diag.Diagnostics {
+	setValue := util.MkLens(d)
+
+	formJSONValues := map[string]interface{}{}
+	for _, formJSONValue := range projectIntegration.FormJSONValues {
+		if formJSONValue.Value != RedactedFormJSONValue {
+			formJSONValues[formJSONValue.Label] = formJSONValue.Value
+		}
+	}
+
+	d.SetId(strconv.Itoa(projectIntegration.ID))
+	setValue("name", projectIntegration.Name)
+	setValue("project_id", projectIntegration.ProjectId)
+	setValue("project_key", projectKey)
+	setValue("master_integration_id", projectIntegration.MasterIntegrationId)
+	setValue("master_integration_name", projectIntegration.MasterIntegrationName)
+	setValue("form_json_values", formJSONValues)
+	setValue("environments", projectIntegration.Environments)
+	errors := setValue("is_internal", projectIntegration.IsInternal)
+
+	if len(errors) > 0 {
+		return diag.Errorf("failed to pack project integration %q", errors)
+	}
+
+	return nil
+}
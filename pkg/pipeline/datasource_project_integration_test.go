@@ -0,0 +1,88 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestAccDatasourceProjectIntegration(t *testing.T) {
+	var integrationId int
+
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	integrationName := fmt.Sprintf("int%d", test.RandomInt())
+	_, fqrn, name := test.MkNames(integrationName, "pipeline_project_integration")
+	dataSourceName := fmt.Sprintf("data.%s", fqrn)
+
+	config := util.ExecuteTemplate("TestDatasourceProjectIntegrationConfig", `
+		data "pipeline_project_integration" "{{ .name }}" {
+			name        = "{{ .integrationName }}"
+			project_key = "{{ .projectKey }}"
+		}
+	`, map[string]interface{}{
+		"name":            name,
+		"integrationName": integrationName,
+		"projectKey":      projectKey,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+			integrationId = acctest.CreateProjectIntegration(t, integrationName, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProjectIntegration(t, integrationId)
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", fmt.Sprintf("%d", integrationId)),
+					resource.TestCheckResourceAttr(dataSourceName, "name", integrationName),
+					resource.TestCheckResourceAttr(dataSourceName, "project_key", projectKey),
+					resource.TestCheckResourceAttrSet(dataSourceName, "project_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "master_integration_id", "78"),
+					resource.TestCheckResourceAttr(dataSourceName, "master_integration_name", "slackKey"),
+					resource.TestCheckResourceAttr(dataSourceName, "form_json_values.url", "http://foo.bar"),
+				),
+			},
+		},
+	})
+}
+
+func TestDatasourceProjectIntegration_notFound(t *testing.T) {
+	integrationName := fmt.Sprintf("int%d", test.RandomInt())
+	_, _, name := test.MkNames(integrationName, "pipeline_project_integration")
+
+	config := util.ExecuteTemplate("TestDatasourceProjectIntegrationConfig", `
+		data "pipeline_project_integration" "{{ .name }}" {
+			name = "{{ .integrationName }}"
+		}
+	`, map[string]interface{}{
+		"name":            name,
+		"integrationName": integrationName,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(fmt.Sprintf(".*no project integration found with name '%s'", integrationName)),
+			},
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func projectIntegrationsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceProjectIntegrationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"master_integration_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return integrations of this master integration, e.g. `github`.",
+			},
+			"project_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Only return integrations of this project.",
+			},
+			"integrations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The Id of the project integration.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the project integration.",
+						},
+						"project_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the project.",
+						},
+						"master_integration_id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The Id of the master integration.",
+						},
+						"master_integration_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the master integration.",
+						},
+						"environments": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "In a project, an array of environment names in which this integration is available.",
+						},
+					},
+				},
+				Description: "The matching project integrations.",
+			},
+		},
+
+		Description: "Gets the project integrations matching the filters, for example every GitHub integration of a project.",
+	}
+}
+
+func dataSourceProjectIntegrationsRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	masterIntegrationName := d.GetString("master_integration_name", false)
+	projectKey := d.GetString("project_key", false)
+
+	req := client.R()
+	if masterIntegrationName != "" {
+		req.SetQueryParam("masterIntegrationNames", masterIntegrationName)
+	}
+	if projectKey != "" {
+		project, err := findProjectByKey(client, projectKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		req.SetQueryParam("projectIds", strconv.Itoa(project.Id))
+	}
+
+	var projectIntegrations []ProjectIntegration
+	_, err := req.SetResult(&projectIntegrations).Get(projectIntegrationsUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("found %d project integrations", len(projectIntegrations)))
+
+	var integrations []interface{}
+	for _, projectIntegration := range projectIntegrations {
+		integrations = append(integrations, map[string]interface{}{
+			"id":                      projectIntegration.ID,
+			"name":                    projectIntegration.Name,
+			"project_id":              projectIntegration.ProjectId,
+			"master_integration_id":   projectIntegration.MasterIntegrationId,
+			"master_integration_name": projectIntegration.MasterIntegrationName,
+			"environments":            projectIntegration.Environments,
+		})
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(projectKey + "/" + masterIntegrationName)))
+	if err := data.Set("integrations", integrations); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
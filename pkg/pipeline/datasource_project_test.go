@@ -50,6 +50,50 @@ func TestAccDatasourceProject(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr(dataSourceName, "name", projectKey),
 					resource.TestCheckResourceAttrSet(dataSourceName, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "key", projectKey),
+					resource.TestCheckResourceAttr(dataSourceName, "display_name", projectKey),
+					resource.TestCheckResourceAttr(dataSourceName, "description", fmt.Sprintf("%s description", projectKey)),
+					resource.TestCheckResourceAttr(dataSourceName, "integrations_count", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "pipeline_sources_count", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "node_pools_count", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDatasourceProject_byKey(t *testing.T) {
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	_, fqrn, name := test.MkNames(projectKey, "pipeline_project")
+	dataSourceName := fmt.Sprintf("data.%s", fqrn)
+
+	config := util.ExecuteTemplate("TestDatasourceProjectConfig", `
+		data "pipeline_project" "{{ .name }}" {
+			key = "{{ .projectKey }}"
+		}
+	`, map[string]interface{}{
+		"name":       name,
+		"projectKey": projectKey,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "key", projectKey),
+					resource.TestCheckResourceAttr(dataSourceName, "name", projectKey),
+					resource.TestCheckResourceAttrSet(dataSourceName, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "integrations_count", "0"),
 				),
 			},
 		},
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func projectsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceProjectsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return projects with a name matching this regular expression.",
+			},
+			"key_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return projects with a key matching this regular expression.",
+			},
+			"projects": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The Pipelines Id of the project.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the project.",
+						},
+						"key": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The key of the project.",
+						},
+					},
+				},
+				Description: "The matching projects.",
+			},
+		},
+
+		Description: "Gets every Pipelines project, optionally filtered by name or key. Useful with `for_each` to create the same node pools or integrations in every project.",
+	}
+}
+
+func dataSourceProjectsRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+
+	nameRegex := d.GetString("name_regex", false)
+	keyRegex := d.GetString("key_regex", false)
+	// an empty expression matches everything. Validated at plan time only when the value is known.
+	nameMatcher, err := regexp.Compile(nameRegex)
+	if err != nil {
+		return diag.Errorf("invalid name_regex '%s': %s", nameRegex, err)
+	}
+	keyMatcher, err := regexp.Compile(keyRegex)
+	if err != nil {
+		return diag.Errorf("invalid key_regex '%s': %s", keyRegex, err)
+	}
+
+	var projects []Project
+	_, err = m.(*resty.Client).R().
+		SetResult(&projects).
+		Get(allProjectsUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("found %d projects", len(projects)))
+
+	var matches []interface{}
+	for _, project := range projects {
+		if !nameMatcher.MatchString(project.Name) || !keyMatcher.MatchString(project.Key) {
+			continue
+		}
+		matches = append(matches, map[string]interface{}{
+			"id":   project.Id,
+			"name": project.Name,
+			"key":  project.Key,
+		})
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(nameRegex + "/" + keyRegex)))
+	if err := data.Set("projects", matches); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
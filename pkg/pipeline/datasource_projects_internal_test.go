@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDataSourceProjectsRead_invalidRegex(t *testing.T) {
+	client, err := buildClient("http://127.0.0.1:0", "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]string{
+		"name_regex": "invalid name_regex",
+		"key_regex":  "invalid key_regex",
+	} {
+		t.Run(key, func(t *testing.T) {
+			data := projectsDataSource().TestResourceData()
+			if err := data.Set(key, "proj-("); err != nil {
+				t.Fatal(err)
+			}
+
+			diags := dataSourceProjectsRead(context.Background(), data, client.SetRetryCount(0))
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, expected) {
+				t.Errorf("expected an error containing '%s', got %v", expected, diags)
+			}
+		})
+	}
+}
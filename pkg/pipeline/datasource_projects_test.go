@@ -0,0 +1,50 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestAccDatasourceProjects(t *testing.T) {
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	_, fqrn, name := test.MkNames(projectKey, "pipeline_projects")
+	dataSourceName := fmt.Sprintf("data.%s", fqrn)
+
+	config := util.ExecuteTemplate("TestDatasourceProjectsConfig", `
+		data "pipeline_projects" "{{ .name }}" {
+			key_regex = "^{{ .projectKey }}$"
+		}
+	`, map[string]interface{}{
+		"name":       name,
+		"projectKey": projectKey,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "projects.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "projects.0.key", projectKey),
+					resource.TestCheckResourceAttr(dataSourceName, "projects.0.name", projectKey),
+					resource.TestCheckResourceAttrSet(dataSourceName, "projects.0.id"),
+				),
+			},
+		},
+	})
+}
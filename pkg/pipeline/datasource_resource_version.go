@@ -0,0 +1,225 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+// PipelineResource is a resource declared in a pipeline source, e.g. an Image or a BuildInfo
+type PipelineResource struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	PipelineSourceId int    `json:"pipelineSourceId"`
+	ProjectId        int    `json:"projectId"`
+	ID               int    `json:"id"`
+}
+
+type ResourceVersion struct {
+	ResourceId         int                    `json:"resourceId"`
+	ContentPropertyBag map[string]interface{} `json:"contentPropertyBag"`
+	CreatedAt          string                 `json:"createdAt"`
+	ID                 int                    `json:"id"`
+}
+
+const (
+	pipelineResourcesUrl = "pipelines/api/v1/resources"
+	resourceVersionsUrl  = "pipelines/api/v1/resourceVersions"
+)
+
+// packResourceVersionProperties returns the properties of the version as strings, encoding the values that
+// aren't strings as JSON
+func packResourceVersionProperties(properties map[string]interface{}) (map[string]interface{}, error) {
+	packed := map[string]interface{}{}
+	for key, value := range properties {
+		if s, ok := value.(string); ok {
+			packed[key] = s
+			continue
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		packed[key] = string(encoded)
+	}
+	return packed, nil
+}
+
+// resourceVersionMatches returns whether the version has all the properties of the filter
+func resourceVersionMatches(properties map[string]interface{}, filter map[string]interface{}) bool {
+	for key, value := range filter {
+		if properties[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// findLatestResourceVersion returns the version with the highest Id whose properties match the filter, or nil. The
+// versions are only sorted by the API when there is no filter, so they are sorted here.
+func findLatestResourceVersion(versions []ResourceVersion, filter map[string]interface{}) (*ResourceVersion, error) {
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ID > versions[j].ID
+	})
+
+	for i := range versions {
+		properties, err := packResourceVersionProperties(versions[i].ContentPropertyBag)
+		if err != nil {
+			return nil, err
+		}
+		if resourceVersionMatches(properties, filter) {
+			return &versions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func resourceVersionDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceResourceVersionRead,
+
+		Schema: map[string]*schema.Schema{
+			"resource_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the Pipelines resource, e.g. an `Image` or `BuildInfo` resource.",
+			},
+			"pipeline_source_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Id of the pipeline source declaring the resource. Narrows down the lookup of the resource.",
+			},
+			"version_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"property_filter"},
+				ValidateFunc:  validation.IntAtLeast(1),
+				Description:   "Id of the version. Default to the latest version.",
+			},
+			"property_filter": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Get the latest version with these properties, e.g. `{ imageTag = \"1.2.3\" }`. All the versions of the resource are fetched to be searched. Conflicts with `version_id`.",
+			},
+			"resource_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the Pipelines resource.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the Pipelines resource, e.g. `Image`, `BuildInfo`, `GitRepo` or `PropertyBag`.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Creation time of the version.",
+			},
+			"properties": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The properties of the version, e.g. `imageTag` of an `Image` or `buildNumber` of a `BuildInfo`. Values that aren't strings are encoded as JSON.",
+			},
+		},
+
+		Description: "Gets a version of a Pipelines resource, e.g. the image tag or build number produced by a pipeline, to deploy it with Terraform.",
+	}
+}
+
+func dataSourceResourceVersionRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	name := d.GetString("resource_name", false)
+	req := client.R().SetQueryParam("names", name)
+	if pipelineSourceId := d.GetInt("pipeline_source_id", false); pipelineSourceId != 0 {
+		req.SetQueryParam("pipelineSourceIds", strconv.Itoa(pipelineSourceId))
+	}
+
+	var resources []PipelineResource
+	_, err := req.SetResult(&resources).Get(pipelineResourcesUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(resources) == 0 {
+		return diag.Errorf("no resource found with name '%s'", name)
+	}
+	if len(resources) > 1 {
+		return diag.Errorf("%d resources found with name '%s', set pipeline_source_id to narrow down the lookup", len(resources), name)
+	}
+	resource := resources[0]
+
+	versionId := d.GetInt("version_id", false)
+	filter := data.Get("property_filter").(map[string]interface{})
+
+	versionsReq := client.R().SetQueryParam("resourceIds", strconv.Itoa(resource.ID))
+	if versionId != 0 {
+		versionsReq.SetQueryParam("resourceVersionIds", strconv.Itoa(versionId))
+	} else if len(filter) == 0 {
+		// only fetch the latest version. With a filter, all the versions are searched.
+		versionsReq.SetQueryParams(map[string]string{
+			"sortBy":    "id",
+			"sortOrder": "-1",
+			"limit":     "1",
+		})
+	}
+
+	var versions []ResourceVersion
+	_, err = versionsReq.SetResult(&versions).Get(resourceVersionsUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tflog.Debug(ctx, fmt.Sprintf("found %d versions of resource %s", len(versions), name))
+
+	version, err := findLatestResourceVersion(versions, filter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if version == nil {
+		if versionId != 0 {
+			return diag.Errorf("no version %d found for resource '%s'", versionId, name)
+		}
+		return diag.Errorf("no version found for resource '%s' matching %v", name, filter)
+	}
+
+	properties, err := packResourceVersionProperties(version.ContentPropertyBag)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(strconv.Itoa(version.ID))
+
+	setValue := util.MkLens(data)
+	errors := setValue("pipeline_source_id", resource.PipelineSourceId)
+	errors = append(errors, setValue("version_id", version.ID)...)
+	errors = append(errors, setValue("resource_id", resource.ID)...)
+	errors = append(errors, setValue("type", resource.Type)...)
+	errors = append(errors, setValue("created_at", version.CreatedAt)...)
+	errors = append(errors, setValue("properties", properties)...)
+	if len(errors) > 0 {
+		return diag.Errorf("failed to pack resource version %q", errors)
+	}
+
+	return nil
+}
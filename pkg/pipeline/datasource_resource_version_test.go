@@ -0,0 +1,135 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestPackResourceVersionProperties(t *testing.T) {
+	properties, err := packResourceVersionProperties(map[string]interface{}{
+		"imageName":   "docker.example.com/app",
+		"imageTag":    "1.2.3",
+		"buildNumber": float64(42),
+		"labels":      []interface{}{"release"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"imageName":   "docker.example.com/app",
+		"imageTag":    "1.2.3",
+		"buildNumber": "42",
+		"labels":      `["release"]`,
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected %v, got %v", expected, properties)
+	}
+
+	if !resourceVersionMatches(properties, map[string]interface{}{"imageTag": "1.2.3", "buildNumber": "42"}) {
+		t.Error("expected the version to match the filter")
+	}
+	if resourceVersionMatches(properties, map[string]interface{}{"imageTag": "1.2.4"}) {
+		t.Error("expected the version not to match the filter")
+	}
+}
+
+func TestFindLatestResourceVersion(t *testing.T) {
+	// the versions aren't returned in order
+	versions := []ResourceVersion{
+		{ID: 2, ContentPropertyBag: map[string]interface{}{"imageTag": "1.0.0"}},
+		{ID: 7, ContentPropertyBag: map[string]interface{}{"imageTag": "1.1.0"}},
+		{ID: 5, ContentPropertyBag: map[string]interface{}{"imageTag": "1.0.0"}},
+	}
+
+	version, err := findLatestResourceVersion(versions, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if version == nil || version.ID != 7 {
+		t.Errorf("expected the latest version 7, got %+v", version)
+	}
+
+	version, err = findLatestResourceVersion(versions, map[string]interface{}{"imageTag": "1.0.0"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if version == nil || version.ID != 5 {
+		t.Errorf("expected the latest matching version 5, got %+v", version)
+	}
+
+	version, err = findLatestResourceVersion(versions, map[string]interface{}{"imageTag": "2.0.0"})
+	if err != nil || version != nil {
+		t.Errorf("expected no version, got %+v %v", version, err)
+	}
+}
+
+func TestDataSourceResourceVersionRead_query(t *testing.T) {
+	versions := []ResourceVersion{
+		{ID: 2, ResourceId: 9, ContentPropertyBag: map[string]interface{}{"imageTag": "1.0.0"}},
+		{ID: 7, ResourceId: 9, ContentPropertyBag: map[string]interface{}{"imageTag": "1.1.0"}},
+		{ID: 5, ResourceId: 9, ContentPropertyBag: map[string]interface{}{"imageTag": "1.0.0"}},
+	}
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/"+pipelineResourcesUrl {
+			_ = json.NewEncoder(w).Encode([]PipelineResource{{ID: 9, Name: "app_image", Type: "Image", PipelineSourceId: 3}})
+			return
+		}
+		query = r.URL.Query()
+		matches := append([]ResourceVersion{}, versions...)
+		if query.Get("sortBy") == "id" && query.Get("sortOrder") == "-1" {
+			sort.Slice(matches, func(i, j int) bool {
+				return matches[i].ID > matches[j].ID
+			})
+		}
+		if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit < len(matches) {
+			matches = matches[:limit]
+		}
+		_ = json.NewEncoder(w).Encode(matches)
+	}))
+	t.Cleanup(server.Close)
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		filter   map[string]interface{}
+		expected string
+		limit    string
+	}{
+		"latest":          {expected: "7", limit: "1"},
+		"property filter": {filter: map[string]interface{}{"imageTag": "1.0.0"}, expected: "5"},
+	}
+	for name, tcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := resourceVersionDataSource().TestResourceData()
+			if err := data.Set("resource_name", "app_image"); err != nil {
+				t.Fatal(err)
+			}
+			if err := data.Set("property_filter", tcase.filter); err != nil {
+				t.Fatal(err)
+			}
+
+			if diags := dataSourceResourceVersionRead(context.Background(), data, client.SetRetryCount(0)); diags.HasError() {
+				t.Fatalf("err: %v", diags)
+			}
+			if data.Id() != tcase.expected {
+				t.Errorf("expected version %s, got '%s'", tcase.expected, data.Id())
+			}
+			// all the versions are searched with a filter
+			if limit := query.Get("limit"); limit != tcase.limit {
+				t.Errorf("expected the limit '%s', got '%s'", tcase.limit, limit)
+			}
+		})
+	}
+}
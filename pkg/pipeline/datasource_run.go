@@ -0,0 +1,251 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+// findRun returns the run with this number, or the run `offset` runs before the latest one
+func findRun(client *resty.Client, pipelineId, runNumber, offset int) (*Run, error) {
+	req := client.R().SetQueryParam("pipelineIds", strconv.Itoa(pipelineId))
+	if runNumber != 0 {
+		req.SetQueryParam("runNumbers", strconv.Itoa(runNumber))
+	} else {
+		// only fetch the latest runs, up to the one at the offset
+		req.SetQueryParams(map[string]string{
+			"sortBy":    "runNumber",
+			"sortOrder": "-1",
+			"limit":     strconv.Itoa(offset + 1),
+		})
+	}
+
+	var runs []Run
+	_, err := req.SetResult(&runs).Get(runsUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if runNumber != 0 {
+		if len(runs) == 0 {
+			return nil, fmt.Errorf("no run found with number %d in pipeline %d", runNumber, pipelineId)
+		}
+		return &runs[0], nil
+	}
+	// the runs are sorted again in case the API ignores the sort
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].RunNumber > runs[j].RunNumber
+	})
+	if len(runs) <= offset {
+		return nil, fmt.Errorf("pipeline %d has only %d runs, no run found with offset %d", pipelineId, len(runs), offset)
+	}
+	return &runs[offset], nil
+}
+
+func runDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRunRead,
+
+		Schema: map[string]*schema.Schema{
+			"pipeline_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The name of the pipeline.",
+			},
+			"pipeline_source_id": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Id of the pipeline source of the pipeline. Narrows down the lookup of the pipeline.",
+			},
+			"branch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "The branch of the pipeline, for multi-branch pipeline sources.",
+			},
+			"run_number": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"offset"},
+				ValidateFunc:  validation.IntAtLeast(1),
+				Description:   "Number of the run. Default to the latest run.",
+			},
+			"offset": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Get the run `offset` runs before the latest one, e.g. `1` for the run before the latest. Conflicts with `run_number`. Default to `0`.",
+			},
+			"wait_for_terminal_state": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait until the run completes, up to the read timeout, e.g. when the data source is read during the apply of a run it depends on. Default to `false`.",
+			},
+			"pipeline_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Id of the pipeline.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the run, e.g. `processing`, `success` or `failure`.",
+			},
+			"started_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Start time of the run.",
+			},
+			"ended_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "End time of the run. Empty while the run is active.",
+			},
+			"duration_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Duration of the run, in seconds.",
+			},
+			"commit_sha": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA of the commit of the pipeline source the run is for.",
+			},
+			"triggered_by": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the user who triggered the run.",
+			},
+			"steps": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Id of the step.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the step.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Status of the step.",
+						},
+						"started_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Start time of the step.",
+						},
+						"ended_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "End time of the step.",
+						},
+					},
+				},
+				Description: "The steps of the run.",
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Description: "Gets the latest run of a pipeline, or a given run, with the status of its steps, e.g. to gate a promotion on the last run of a pipeline.",
+	}
+}
+
+func dataSourceRunRead(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d := &util.ResourceData{ResourceData: data}
+	client := m.(*resty.Client)
+
+	pipeline, err := findPipeline(client, d.GetString("pipeline_name", false), d.GetInt("pipeline_source_id", false), d.GetString("branch", false))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	run, err := findRun(client, pipeline.ID, d.GetInt("run_number", false), d.GetInt("offset", false))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.GetBool("wait_for_terminal_state", false) && !isTerminalStatus(run.StatusCode) {
+		runId := run.ID
+		err := retry.RetryContext(ctx, data.Timeout(schema.TimeoutRead), func() *retry.RetryError {
+			run, err = getRun(client, runId)
+			if err != nil {
+				return retry.NonRetryableError(err)
+			}
+			if run == nil {
+				return retry.NonRetryableError(fmt.Errorf("run %d not found", runId))
+			}
+			tflog.Debug(ctx, fmt.Sprintf("run %d is %s", runId, statusName(run.StatusCode)))
+
+			if !isTerminalStatus(run.StatusCode) {
+				return retry.RetryableError(fmt.Errorf("run %d is %s", runId, statusName(run.StatusCode)))
+			}
+			return nil
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	var steps []Step
+	_, err = client.R().
+		SetQueryParam("runIds", strconv.Itoa(run.ID)).
+		SetResult(&steps).
+		Get(stepsUrl)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	packedSteps := []interface{}{}
+	for _, step := range steps {
+		packedSteps = append(packedSteps, map[string]interface{}{
+			"id":         step.ID,
+			"name":       step.Name,
+			"status":     statusName(step.StatusCode),
+			"started_at": step.StartedAt,
+			"ended_at":   step.EndedAt,
+		})
+	}
+
+	data.SetId(strconv.Itoa(run.ID))
+
+	setValue := util.MkLens(data)
+	errors := setValue("pipeline_id", pipeline.ID)
+	errors = append(errors, setValue("run_number", run.RunNumber)...)
+	errors = append(errors, setValue("status", statusName(run.StatusCode))...)
+	errors = append(errors, setValue("started_at", run.StartedAt)...)
+	errors = append(errors, setValue("ended_at", run.EndedAt)...)
+	errors = append(errors, setValue("duration_seconds", run.DurationSeconds)...)
+	errors = append(errors, setValue("commit_sha", run.StaticPropertyBag.CommitSha)...)
+	errors = append(errors, setValue("triggered_by", run.StaticPropertyBag.TriggeredByUserName)...)
+	errors = append(errors, setValue("steps", packedSteps)...)
+	if len(errors) > 0 {
+		return diag.Errorf("failed to pack run %q", errors)
+	}
+
+	return nil
+}
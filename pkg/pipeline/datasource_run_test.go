@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestFindRun(t *testing.T) {
+	runs := []Run{{RunNumber: 2, ID: 12}, {RunNumber: 4, ID: 14}, {RunNumber: 1, ID: 11}, {RunNumber: 3, ID: 13}}
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		matches := []Run{}
+		for _, run := range runs {
+			if runNumber := query.Get("runNumbers"); runNumber == "" || runNumber == strconv.Itoa(run.RunNumber) {
+				matches = append(matches, run)
+			}
+		}
+		if query.Get("sortBy") == "runNumber" && query.Get("sortOrder") == "-1" {
+			sort.Slice(matches, func(i, j int) bool {
+				return matches[i].RunNumber > matches[j].RunNumber
+			})
+		}
+		if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit < len(matches) {
+			matches = matches[:limit]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(matches)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetRetryCount(0)
+
+	testCases := map[string]struct {
+		runNumber, offset, expected int
+		limit                       string
+	}{
+		"latest":     {expected: 14, limit: "1"},
+		"offset":     {offset: 2, expected: 12, limit: "3"},
+		"run number": {runNumber: 3, expected: 13},
+	}
+	for name, tcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			run, err := findRun(client, 1, tcase.runNumber, tcase.offset)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if run.ID != tcase.expected {
+				t.Errorf("expected run %d, got %d", tcase.expected, run.ID)
+			}
+			// only the runs up to the offset are fetched
+			if limit := query.Get("limit"); limit != tcase.limit {
+				t.Errorf("expected the limit '%s', got '%s'", tcase.limit, limit)
+			}
+		})
+	}
+
+	if _, err := findRun(client, 1, 0, 4); err == nil {
+		t.Error("expected an error with an offset past the first run")
+	}
+}
@@ -0,0 +1,115 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type NodeCredentialsEphemeralResource struct {
+	client *resty.Client
+}
+
+type NodeCredentialsEphemeralResourceModel struct {
+	NodeId            types.Int64  `tfsdk:"node_id"`
+	Token             types.String `tfsdk:"token"`
+	InitScript        types.String `tfsdk:"init_script"`
+	InitScriptWindows types.String `tfsdk:"init_script_windows"`
+}
+
+var _ ephemeral.EphemeralResourceWithConfigure = &NodeCredentialsEphemeralResource{}
+
+func NewNodeCredentialsEphemeralResource() ephemeral.EphemeralResource {
+	return &NodeCredentialsEphemeralResource{}
+}
+
+func (r *NodeCredentialsEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_credentials"
+}
+
+func (r *NodeCredentialsEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"node_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "Id of the node.",
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token of the node.",
+			},
+			"init_script": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Script initializing the node on Linux. Only set for manually initialized nodes.",
+			},
+			"init_script_windows": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "PowerShell script initializing the node on Windows. Only set for manually initialized nodes.",
+			},
+		},
+		Description: "Gets the token and init scripts of a node without storing them in the state or the plan. Requires Terraform 1.10 or later.",
+	}
+}
+
+func (r *NodeCredentialsEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*resty.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *resty.Client, got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *NodeCredentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data NodeCredentialsEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := strconv.FormatInt(data.NodeId.ValueInt64(), 10)
+	tflog.Debug(ctx, fmt.Sprintf("opening credentials of node %s", id))
+
+	var node Node
+	_, err := r.client.R().
+		SetResult(&node).
+		Get(nodesUrl + "/" + id)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get the node", err.Error())
+		return
+	}
+
+	initScript, initScriptWindows := "", ""
+	if !node.IsAutoInitialized {
+		initScript, err = getNodeInitScript(r.client, id, node.SystemPropertyBag.Token, nodeInitPlatformLinux)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get the init script of the node", err.Error())
+			return
+		}
+		initScriptWindows, err = getNodeInitScript(r.client, id, node.SystemPropertyBag.Token, nodeInitPlatformWindows)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get the Windows init script of the node", err.Error())
+			return
+		}
+	}
+
+	data.Token = types.StringValue(node.SystemPropertyBag.Token)
+	data.InitScript = types.StringValue(initScript)
+	data.InitScriptWindows = types.StringValue(initScriptWindows)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
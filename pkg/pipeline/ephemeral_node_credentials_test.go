@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// openNodeCredentials opens the credentials of the node as Terraform does, with a resource configured with the client
+func openNodeCredentials(t *testing.T, client *resty.Client, nodeId int64) (*ephemeral.OpenResponse, NodeCredentialsEphemeralResourceModel) {
+	ctx := context.Background()
+	credentials := NewNodeCredentialsEphemeralResource().(*NodeCredentialsEphemeralResource)
+
+	configureResp := &ephemeral.ConfigureResponse{}
+	credentials.Configure(ctx, ephemeral.ConfigureRequest{ProviderData: client}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("err: %v", configureResp.Diagnostics)
+	}
+
+	schemaResp := &ephemeral.SchemaResponse{}
+	credentials.Schema(ctx, ephemeral.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"node_id":             tftypes.NewValue(tftypes.Number, nodeId),
+			"token":               tftypes.NewValue(tftypes.String, nil),
+			"init_script":         tftypes.NewValue(tftypes.String, nil),
+			"init_script_windows": tftypes.NewValue(tftypes.String, nil),
+		}),
+	}
+
+	resp := &ephemeral.OpenResponse{
+		Result: tfsdk.EphemeralResultData{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	credentials.Open(ctx, ephemeral.OpenRequest{Config: config}, resp)
+
+	var result NodeCredentialsEphemeralResourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.Result.Get(ctx, &result)...)
+	}
+	return resp, result
+}
+
+func TestNodeCredentialsOpen(t *testing.T) {
+	_, client := newFakeNodeServer(t, nodeStatusOnline)
+
+	resp, result := openNodeCredentials(t, client, 3)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("err: %v", resp.Diagnostics)
+	}
+	for name, values := range map[string][2]string{
+		"token":               {result.Token.ValueString(), "token-1"},
+		"init_script":         {result.InitScript.ValueString(), "linux script for token-1"},
+		"init_script_windows": {result.InitScriptWindows.ValueString(), "windows script for token-1"},
+	} {
+		if values[0] != values[1] {
+			t.Errorf("expected %s to be '%s', got '%s'", name, values[1], values[0])
+		}
+	}
+}
+
+func TestNodeCredentialsOpen_autoInitialized(t *testing.T) {
+	fake, client := newFakeNodeServer(t, nodeStatusOnline)
+	fake.node.IsAutoInitialized = true
+
+	resp, result := openNodeCredentials(t, client, 3)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("err: %v", resp.Diagnostics)
+	}
+	if result.Token.ValueString() != "token-1" {
+		t.Errorf("expected the token of the node, got '%s'", result.Token.ValueString())
+	}
+	if result.InitScript.ValueString() != "" || result.InitScriptWindows.ValueString() != "" {
+		t.Errorf("expected no init scripts for an auto initialized node, got '%s' and '%s'", result.InitScript.ValueString(), result.InitScriptWindows.ValueString())
+	}
+}
+
+func TestNodeCredentialsOpen_nodeNotFound(t *testing.T) {
+	_, client := newFakeNodeServer(t, nodeStatusOnline)
+
+	resp, _ := openNodeCredentials(t, client, 4)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Failed to get the node" {
+		t.Errorf("expected the missing node to be reported, got %v", resp.Diagnostics)
+	}
+}
+
+func TestNodeCredentialsOpen_initScriptError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/initScript") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 3, "friendlyName": "node", "isAutoInitialized": false, "systemPropertyBag": {"token": "token-1"}}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, _ := openNodeCredentials(t, client.SetRetryCount(0), 3)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Failed to get the init script of the node" {
+		t.Errorf("expected the failed init script to be reported, got %v", resp.Diagnostics)
+	}
+}
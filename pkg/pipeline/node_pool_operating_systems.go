@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const supportedOperatingSystemsUrl = "pipelines/api/v1/nodePools/supportedOperatingSystems"
+
+type SupportedOperatingSystem struct {
+	Architecture    string `json:"architecture"`
+	OperatingSystem string `json:"operatingSystem"`
+}
+
+// defaultSupportedOperatingSystems is used to validate node pools when the provider doesn't fetch the list from the server
+var defaultSupportedOperatingSystems = map[string][]string{
+	"x86_64": {
+		"Ubuntu_18.04",
+		"Ubuntu_20.04",
+		"Ubuntu_22.04",
+		"CentOS_7",
+		"CentOS_8",
+		"RHEL_7",
+		"RHEL_8",
+		"RHEL_9",
+		"WindowsServer_2019",
+		"WindowsServer_2022",
+	},
+	"ARM64": {
+		"Ubuntu_20.04",
+		"Ubuntu_22.04",
+		"RHEL_8",
+	},
+}
+
+// supportedOperatingSystemsCache keeps the operating systems fetched from each server, see `fetch_supported_operating_systems`
+var supportedOperatingSystemsCache = struct {
+	sync.RWMutex
+	architectures map[string]map[string][]string
+}{architectures: map[string]map[string][]string{}}
+
+func fetchSupportedOperatingSystems(client *resty.Client) error {
+	operatingSystems := []SupportedOperatingSystem{}
+	_, err := client.R().
+		SetResult(&operatingSystems).
+		Get(supportedOperatingSystemsUrl)
+	if err != nil {
+		return err
+	}
+
+	architectures := map[string][]string{}
+	for _, os := range operatingSystems {
+		architectures[os.Architecture] = append(architectures[os.Architecture], os.OperatingSystem)
+	}
+
+	supportedOperatingSystemsCache.Lock()
+	defer supportedOperatingSystemsCache.Unlock()
+	supportedOperatingSystemsCache.architectures[client.HostURL] = architectures
+
+	return nil
+}
+
+func supportedOperatingSystems(client *resty.Client) map[string][]string {
+	if client == nil {
+		return defaultSupportedOperatingSystems
+	}
+
+	supportedOperatingSystemsCache.RLock()
+	defer supportedOperatingSystemsCache.RUnlock()
+	if architectures, ok := supportedOperatingSystemsCache.architectures[client.HostURL]; ok && len(architectures) > 0 {
+		return architectures
+	}
+	return defaultSupportedOperatingSystems
+}
+
+func validateNodePoolOperatingSystem(client *resty.Client, architecture, operatingSystem string) error {
+	architectures := supportedOperatingSystems(client)
+
+	operatingSystems, ok := architectures[architecture]
+	if !ok {
+		names := make([]string, 0, len(architectures))
+		for name := range architectures {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("architecture '%s' is not supported, expected one of: %s", architecture, strings.Join(names, ", "))
+	}
+
+	for _, os := range operatingSystems {
+		if os == operatingSystem {
+			return nil
+		}
+	}
+	return fmt.Errorf("operating system '%s' is not supported for architecture '%s', expected one of: %s", operatingSystem, architecture, strings.Join(operatingSystems, ", "))
+}
+
+func isWindowsOperatingSystem(operatingSystem string) bool {
+	return strings.HasPrefix(strings.ToLower(operatingSystem), "windows")
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateNodePoolOperatingSystem(t *testing.T) {
+	cases := []struct {
+		architecture    string
+		operatingSystem string
+		valid           bool
+	}{
+		{"x86_64", "Ubuntu_20.04", true},
+		{"x86_64", "WindowsServer_2019", true},
+		{"ARM64", "Ubuntu_22.04", true},
+		{"ARM64", "WindowsServer_2019", false},
+		{"x86_64", "Ubuntu_10.04", false},
+		{"sparc", "Ubuntu_20.04", false},
+	}
+
+	for _, c := range cases {
+		err := validateNodePoolOperatingSystem(nil, c.architecture, c.operatingSystem)
+		if c.valid && err != nil {
+			t.Errorf("expected %s on %s to be valid, got %s", c.operatingSystem, c.architecture, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("expected %s on %s to be invalid", c.operatingSystem, c.architecture)
+		}
+	}
+}
+
+func TestValidateNodePoolOperatingSystem_fetched(t *testing.T) {
+	client := resty.New().SetBaseURL("http://fetched.example.com")
+
+	supportedOperatingSystemsCache.Lock()
+	supportedOperatingSystemsCache.architectures[client.HostURL] = map[string][]string{
+		"ARM64": {"WindowsServer_2022"},
+	}
+	supportedOperatingSystemsCache.Unlock()
+
+	if err := validateNodePoolOperatingSystem(client, "ARM64", "WindowsServer_2022"); err != nil {
+		t.Errorf("expected the fetched operating systems to be used, got %s", err)
+	}
+	if err := validateNodePoolOperatingSystem(client, "x86_64", "Ubuntu_20.04"); err == nil {
+		t.Error("expected x86_64 to be invalid with the fetched operating systems")
+	}
+}
+
+func TestNodePoolCustomizeDiff_operatingSystem(t *testing.T) {
+	nodePoolResource := pipelineNodePoolResource()
+	config := func(operatingSystem string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":             "my-node-pool",
+			"project_id":       1,
+			"is_on_demand":     false,
+			"architecture":     "x86_64",
+			"operating_system": operatingSystem,
+		})
+	}
+
+	if _, err := nodePoolResource.Diff(context.Background(), nil, config("Ubuntu_10.04"), nil); err == nil {
+		t.Error("expected an unsupported operating system to be rejected on create")
+	}
+
+	// an existing node pool with an operating system missing from the list, e.g. newly supported by the server
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":               "1",
+			"name":             "my-node-pool",
+			"project_id":       "1",
+			"is_on_demand":     "false",
+			"architecture":     "x86_64",
+			"operating_system": "Ubuntu_24.04",
+			"delete_behavior":  nodePoolDeleteForce,
+		},
+	}
+	if _, err := nodePoolResource.Diff(context.Background(), state, config("Ubuntu_24.04"), nil); err != nil {
+		t.Errorf("expected an unchanged operating system not to be validated, got %s", err)
+	}
+	if _, err := nodePoolResource.Diff(context.Background(), state, config("Ubuntu_10.04"), nil); err == nil {
+		t.Error("expected a changed unsupported operating system to be rejected")
+	}
+}
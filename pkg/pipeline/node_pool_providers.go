@@ -0,0 +1,382 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+// NodePoolProviderSettings holds where the nodes of a dynamic node pool are provisioned. Not every field
+// applies to every provider; nodePoolProviders maps the attributes of each provider block to these fields.
+type NodePoolProviderSettings struct {
+	Provider       string            `json:"provider"`
+	IntegrationId  int               `json:"integrationId"`
+	InstanceType   string            `json:"instanceType,omitempty"`
+	Region         string            `json:"region,omitempty"`
+	Zone           string            `json:"zone,omitempty"`
+	Image          string            `json:"image,omitempty"`
+	Subnet         string            `json:"subnet,omitempty"`
+	SecurityGroups []string          `json:"securityGroups,omitempty"`
+	DiskSizeInGB   int               `json:"diskSizeInGB,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	MinNodes       int               `json:"minNodes,omitempty"`
+	MaxNodes       int               `json:"maxNodes,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+	NodeSelector   map[string]string `json:"nodeSelector,omitempty"`
+}
+
+type nodePoolProvider struct {
+	name string
+	// attributes maps the attribute names of the provider block to the fields of NodePoolProviderSettings
+	attributes map[string]string
+	schema     map[string]*schema.Schema
+}
+
+var nodePoolProviderBlocks = []string{"aws", "gcp", "azure", "kubernetes"}
+
+var nodePoolProviderCommonSchema = map[string]*schema.Schema{
+	"integration_id": {
+		Type:         schema.TypeInt,
+		Required:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "Id of the project integration with the credentials of the cloud provider.",
+	},
+	"disk_size_in_gb": {
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "Size of the disk of each node, in GB.",
+	},
+	"min_nodes": {
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  "Minimum number of nodes kept running in the pool.",
+	},
+	"max_nodes": {
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ValidateFunc: validation.IntAtLeast(0),
+		Description:  "Maximum number of nodes provisioned in the pool.",
+	},
+}
+
+var nodePoolProviders = map[string]nodePoolProvider{
+	"aws": {
+		name: "AWS",
+		attributes: map[string]string{
+			"instance_type":      "InstanceType",
+			"region":             "Region",
+			"availability_zone":  "Zone",
+			"ami_id":             "Image",
+			"subnet_id":          "Subnet",
+			"security_group_ids": "SecurityGroups",
+			"tags":               "Tags",
+		},
+		schema: map[string]*schema.Schema{
+			"instance_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "EC2 instance type of the nodes, e.g. `c5.xlarge`.",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "AWS region where the nodes are started.",
+			},
+			"availability_zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Availability zone where the nodes are started.",
+			},
+			"ami_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Id of the AMI to start the nodes from.",
+			},
+			"subnet_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Id of the VPC subnet of the nodes.",
+			},
+			"security_group_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Ids of the security groups attached to the nodes.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Tags added to the EC2 instances.",
+			},
+		},
+	},
+	"gcp": {
+		name: "GCP",
+		attributes: map[string]string{
+			"machine_type": "InstanceType",
+			"region":       "Region",
+			"zone":         "Zone",
+			"image":        "Image",
+			"subnetwork":   "Subnet",
+			"network_tags": "SecurityGroups",
+			"labels":       "Tags",
+		},
+		schema: map[string]*schema.Schema{
+			"machine_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Compute Engine machine type of the nodes, e.g. `n2-standard-4`.",
+			},
+			"region": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "GCP region where the nodes are started.",
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Zone where the nodes are started.",
+			},
+			"image": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Boot disk image of the nodes.",
+			},
+			"subnetwork": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Name or self link of the subnetwork of the nodes.",
+			},
+			"network_tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Network tags of the nodes, used by the firewall rules.",
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Labels added to the Compute Engine instances.",
+			},
+		},
+	},
+	"azure": {
+		name: "Azure",
+		attributes: map[string]string{
+			"vm_size":                    "InstanceType",
+			"location":                   "Region",
+			"availability_zone":          "Zone",
+			"image":                      "Image",
+			"subnet_id":                  "Subnet",
+			"network_security_group_ids": "SecurityGroups",
+			"tags":                       "Tags",
+		},
+		schema: map[string]*schema.Schema{
+			"vm_size": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Size of the virtual machines, e.g. `Standard_D4s_v3`.",
+			},
+			"location": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Azure location where the nodes are started.",
+			},
+			"availability_zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Availability zone where the nodes are started.",
+			},
+			"image": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Id or URN of the image to start the nodes from.",
+			},
+			"subnet_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Id of the virtual network subnet of the nodes.",
+			},
+			"network_security_group_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Ids of the network security groups attached to the nodes.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Tags added to the virtual machines.",
+			},
+		},
+	},
+	"kubernetes": {
+		name: "Kubernetes",
+		attributes: map[string]string{
+			"namespace":     "Namespace",
+			"node_selector": "NodeSelector",
+			"labels":        "Tags",
+		},
+		schema: map[string]*schema.Schema{
+			"namespace": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Namespace where the build node pods are created.",
+			},
+			"node_selector": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Labels of the cluster nodes the build node pods can be scheduled on.",
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Labels added to the build node pods.",
+			},
+		},
+	},
+}
+
+func nodePoolProviderSchema() map[string]*schema.Schema {
+	providerSchema := map[string]*schema.Schema{}
+	for _, block := range nodePoolProviderBlocks {
+		provider := nodePoolProviders[block]
+
+		var conflictsWith []string
+		for _, other := range nodePoolProviderBlocks {
+			if other != block {
+				conflictsWith = append(conflictsWith, other)
+			}
+		}
+
+		providerSchema[block] = &schema.Schema{
+			Type:          schema.TypeList,
+			Optional:      true,
+			MaxItems:      1,
+			ConflictsWith: conflictsWith,
+			Elem: &schema.Resource{
+				Schema: util.MergeMaps(nodePoolProviderCommonSchema, provider.schema),
+			},
+			Description: fmt.Sprintf("Provision the nodes of the dynamic node pool on %s. Only valid when `is_on_demand` is `true`.", provider.name),
+		}
+	}
+	return providerSchema
+}
+
+func unpackNodePoolProviderSettings(d *util.ResourceData) *NodePoolProviderSettings {
+	for _, block := range nodePoolProviderBlocks {
+		blocks := d.Get(block).([]interface{})
+		if len(blocks) == 0 || blocks[0] == nil {
+			continue
+		}
+		values := blocks[0].(map[string]interface{})
+		provider := nodePoolProviders[block]
+
+		settings := NodePoolProviderSettings{
+			Provider:      provider.name,
+			IntegrationId: values["integration_id"].(int),
+			DiskSizeInGB:  values["disk_size_in_gb"].(int),
+			MinNodes:      values["min_nodes"].(int),
+			MaxNodes:      values["max_nodes"].(int),
+		}
+		for attribute, field := range provider.attributes {
+			switch field {
+			case "InstanceType":
+				settings.InstanceType = values[attribute].(string)
+			case "Region":
+				settings.Region = values[attribute].(string)
+			case "Zone":
+				settings.Zone = values[attribute].(string)
+			case "Image":
+				settings.Image = values[attribute].(string)
+			case "Subnet":
+				settings.Subnet = values[attribute].(string)
+			case "Namespace":
+				settings.Namespace = values[attribute].(string)
+			case "SecurityGroups":
+				settings.SecurityGroups = util.CastToStringArr(values[attribute].(*schema.Set).List())
+			case "Tags":
+				settings.Tags = castToStringMap(values[attribute].(map[string]interface{}))
+			case "NodeSelector":
+				settings.NodeSelector = castToStringMap(values[attribute].(map[string]interface{}))
+			}
+		}
+		return &settings
+	}
+	return nil
+}
+
+func packNodePoolProviderSettings(setValue util.Lens, settings *NodePoolProviderSettings) []error {
+	var errors []error
+	for _, block := range nodePoolProviderBlocks {
+		provider := nodePoolProviders[block]
+		if settings == nil || settings.Provider != provider.name {
+			errors = append(errors, setValue(block, nil)...)
+			continue
+		}
+
+		values := map[string]interface{}{
+			"integration_id":  settings.IntegrationId,
+			"disk_size_in_gb": settings.DiskSizeInGB,
+			"min_nodes":       settings.MinNodes,
+			"max_nodes":       settings.MaxNodes,
+		}
+		for attribute, field := range provider.attributes {
+			switch field {
+			case "InstanceType":
+				values[attribute] = settings.InstanceType
+			case "Region":
+				values[attribute] = settings.Region
+			case "Zone":
+				values[attribute] = settings.Zone
+			case "Image":
+				values[attribute] = settings.Image
+			case "Subnet":
+				values[attribute] = settings.Subnet
+			case "Namespace":
+				values[attribute] = settings.Namespace
+			case "SecurityGroups":
+				values[attribute] = settings.SecurityGroups
+			case "Tags":
+				values[attribute] = settings.Tags
+			case "NodeSelector":
+				values[attribute] = settings.NodeSelector
+			}
+		}
+		errors = append(errors, setValue(block, []interface{}{values})...)
+	}
+	return errors
+}
+
+func castToStringMap(values map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = v.(string)
+	}
+	return result
+}
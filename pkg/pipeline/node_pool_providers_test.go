@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestNodePoolProviderSettings(t *testing.T) {
+	testCases := map[string]struct {
+		block    string
+		values   map[string]interface{}
+		expected NodePoolProviderSettings
+	}{
+		"aws": {
+			block: "aws",
+			values: map[string]interface{}{
+				"integration_id":     12,
+				"instance_type":      "c5.xlarge",
+				"region":             "us-east-1",
+				"ami_id":             "ami-123456",
+				"security_group_ids": []interface{}{"sg-1"},
+				"tags":               map[string]interface{}{"team": "platform"},
+				"min_nodes":          1,
+				"max_nodes":          5,
+			},
+			expected: NodePoolProviderSettings{
+				Provider:       "AWS",
+				IntegrationId:  12,
+				InstanceType:   "c5.xlarge",
+				Region:         "us-east-1",
+				Image:          "ami-123456",
+				SecurityGroups: []string{"sg-1"},
+				Tags:           map[string]string{"team": "platform"},
+				MinNodes:       1,
+				MaxNodes:       5,
+			},
+		},
+		"kubernetes": {
+			block: "kubernetes",
+			values: map[string]interface{}{
+				"integration_id": 7,
+				"namespace":      "builds",
+				"node_selector":  map[string]interface{}{"pool": "ci"},
+			},
+			expected: NodePoolProviderSettings{
+				Provider:      "Kubernetes",
+				IntegrationId: 7,
+				Namespace:     "builds",
+				NodeSelector:  map[string]string{"pool": "ci"},
+				Tags:          map[string]string{},
+			},
+		},
+	}
+
+	for name, tcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := pipelineNodePoolResource().TestResourceData()
+			if err := data.Set(tcase.block, []interface{}{tcase.values}); err != nil {
+				t.Fatalf("error creating test schema %v", err)
+			}
+
+			settings := unpackNodePoolProviderSettings(&util.ResourceData{ResourceData: data})
+			if settings == nil {
+				t.Fatalf("expected settings for %s", tcase.block)
+			}
+			if !reflect.DeepEqual(*settings, tcase.expected) {
+				t.Errorf("unpacked %+v; expected %+v", *settings, tcase.expected)
+			}
+
+			packed := pipelineNodePoolResource().TestResourceData()
+			if errs := packNodePoolProviderSettings(util.MkLens(packed), settings); len(errs) > 0 {
+				t.Fatalf("error packing settings %v", errs)
+			}
+			roundTrip := unpackNodePoolProviderSettings(&util.ResourceData{ResourceData: packed})
+			if !reflect.DeepEqual(settings, roundTrip) {
+				t.Errorf("round trip returned %+v; expected %+v", roundTrip, settings)
+			}
+		})
+	}
+}
+
+func TestPackNodePoolProviderSettings_errors(t *testing.T) {
+	// only the first block fails, its error must not be overwritten by the blocks packed after it
+	failingBlock := nodePoolProviderBlocks[0]
+	setValue := func(key string, value interface{}) []error {
+		if key == failingBlock {
+			return []error{fmt.Errorf("failed to set %s", key)}
+		}
+		return nil
+	}
+
+	errs := packNodePoolProviderSettings(setValue, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), failingBlock) {
+		t.Errorf("expected the error of block %s, got %v", failingBlock, errs)
+	}
+}
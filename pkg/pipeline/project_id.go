@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+// projectIdLookup is a lookup of a project id, done is closed once id or err is set
+type projectIdLookup struct {
+	done chan struct{}
+	id   int
+	err  error
+}
+
+// projectIdCache keeps the Pipelines project id of each project key, so resources using `project_key` only look
+// the project up once. The lookups are kept per client, i.e. per configured provider, and failed lookups are dropped
+// so the next resource tries again.
+var projectIdCache = struct {
+	sync.Mutex
+	lookups map[*resty.Client]map[string]*projectIdLookup
+}{lookups: map[*resty.Client]map[string]*projectIdLookup{}}
+
+func getProjectIdByKey(client *resty.Client, projectKey string) (int, error) {
+	projectIdCache.Lock()
+	lookups, ok := projectIdCache.lookups[client]
+	if !ok {
+		lookups = map[string]*projectIdLookup{}
+		projectIdCache.lookups[client] = lookups
+	}
+	lookup, ok := lookups[projectKey]
+	if ok {
+		// another resource is looking the project up, or already did
+		projectIdCache.Unlock()
+		<-lookup.done
+		return lookup.id, lookup.err
+	}
+	lookup = &projectIdLookup{done: make(chan struct{})}
+	lookups[projectKey] = lookup
+	projectIdCache.Unlock()
+
+	// the lookup runs outside of the lock, so the lookups of different keys run concurrently
+	project, err := findProjectByKey(client, projectKey)
+	if err != nil {
+		lookup.err = err
+		projectIdCache.Lock()
+		delete(lookups, projectKey)
+		projectIdCache.Unlock()
+	} else {
+		lookup.id = project.Id
+	}
+	close(lookup.done)
+
+	return lookup.id, lookup.err
+}
+
+// resolveProjectId returns the id of the project, looking it up from `project_key` if set
+func resolveProjectId(d *util.ResourceData, m interface{}) (int, error) {
+	if projectKey := d.GetString("project_key", false); projectKey != "" {
+		return getProjectIdByKey(m.(*resty.Client), projectKey)
+	}
+	return d.GetInt("project_id", false), nil
+}
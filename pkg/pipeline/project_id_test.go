@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func newFakeProjectsServer(t *testing.T, projects *[]Project, requests *int32) *resty.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		// slow enough for concurrent lookups to overlap
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(*projects)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.SetRetryCount(0)
+}
+
+func TestResolveProjectId(t *testing.T) {
+	var requests int32
+	projects := []Project{{Name: "myproj", Key: "myproj", Id: 7}}
+	client := newFakeProjectsServer(t, &projects, &requests)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := pipelineNodePoolResource().TestResourceData()
+			if err := data.Set("project_key", "myproj"); err != nil {
+				t.Error(err)
+				return
+			}
+			id, err := resolveProjectId(&util.ResourceData{ResourceData: data}, client)
+			if err != nil || id != 7 {
+				t.Errorf("expected project 7, got %d %v", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if requests != 1 {
+		t.Errorf("expected the project to be looked up once, got %d requests", requests)
+	}
+
+	data := pipelineNodePoolResource().TestResourceData()
+	if err := data.Set("project_id", 3); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := resolveProjectId(&util.ResourceData{ResourceData: data}, client); err != nil || id != 3 {
+		t.Errorf("expected project_id 3 to be used as-is, got %d %v", id, err)
+	}
+}
+
+func TestGetProjectIdByKey_failedLookupNotCached(t *testing.T) {
+	var requests int32
+	projects := []Project{}
+	client := newFakeProjectsServer(t, &projects, &requests)
+
+	if _, err := getProjectIdByKey(client, "newproj"); err == nil {
+		t.Fatal("expected an error for a missing project")
+	}
+
+	// the project is created later in the same run
+	projects = []Project{{Name: "newproj", Key: "newproj", Id: 9}}
+	id, err := getProjectIdByKey(client, "newproj")
+	if err != nil || id != 9 {
+		t.Errorf("expected project 9 after the failed lookup, got %d %v", id, err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the failed lookup to be retried, got %d requests", requests)
+	}
+}
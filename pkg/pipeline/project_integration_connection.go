@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// ProjectIntegrationConnectionTest POST/GET {{ host }}/pipelines/api/v1/projectintegrations/{{projectIntegrationId}}/test
+type ProjectIntegrationConnectionTest struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	connectionTestSuccess = "success"
+	connectionTestFailure = "failure"
+)
+
+// What happens to a new project integration when its connection test fails, see `on_connection_failure`
+const (
+	connectionFailureTaint  = "taint"
+	connectionFailureDelete = "delete"
+)
+
+// testProjectIntegrationConnection starts the connection test of the project integration, and polls it until the
+// server accepts or rejects the connection
+func testProjectIntegrationConnection(ctx context.Context, client *resty.Client, id string, timeout time.Duration) error {
+	tflog.Debug(ctx, "testProjectIntegrationConnection")
+
+	connectionTestUrl := projectIntegrationsUrl + "/" + id + "/test"
+	_, err := client.R().Post(connectionTestUrl)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		var connectionTest ProjectIntegrationConnectionTest
+		_, err := client.R().
+			SetResult(&connectionTest).
+			Get(connectionTestUrl)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("connection test of project integration %s: %+v", id, connectionTest))
+
+		switch connectionTest.Status {
+		case connectionTestSuccess:
+			return nil
+		case connectionTestFailure:
+			return retry.NonRetryableError(fmt.Errorf("connection test of project integration %s failed: %s", id, connectionTest.Message))
+		default:
+			return retry.RetryableError(fmt.Errorf("connection test of project integration %s is %s", id, connectionTest.Status))
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// newFakeConnectionTestServer serves the connection test of project integration 42, with the given statuses
+// returned in turn by the polls, the last one being repeated
+func newFakeConnectionTestServer(t *testing.T, statuses ...ProjectIntegrationConnectionTest) (*resty.Client, *int32) {
+	var started, polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+projectIntegrationsUrl+"/42/test" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPost {
+			atomic.AddInt32(&started, 1)
+			return
+		}
+		if atomic.LoadInt32(&started) == 0 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		i := int(atomic.AddInt32(&polls, 1)) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(statuses[i])
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.SetRetryCount(0), &polls
+}
+
+func TestProjectIntegrationConnection(t *testing.T) {
+	client, polls := newFakeConnectionTestServer(t,
+		ProjectIntegrationConnectionTest{Status: "pending"},
+		ProjectIntegrationConnectionTest{Status: connectionTestSuccess},
+	)
+
+	if err := testProjectIntegrationConnection(context.Background(), client, "42", time.Minute); err != nil {
+		t.Errorf("expected the connection test to succeed, got %s", err)
+	}
+	if *polls != 2 {
+		t.Errorf("expected the connection test to be polled until it succeeded, got %d polls", *polls)
+	}
+}
+
+func TestProjectIntegrationConnection_failure(t *testing.T) {
+	client, _ := newFakeConnectionTestServer(t,
+		ProjectIntegrationConnectionTest{Status: connectionTestFailure, Message: "Bad credentials"},
+	)
+
+	err := testProjectIntegrationConnection(context.Background(), client, "42", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("expected the message of the server, got %v", err)
+	}
+}
+
+func TestProjectIntegrationConnection_timeout(t *testing.T) {
+	client, _ := newFakeConnectionTestServer(t,
+		ProjectIntegrationConnectionTest{Status: "pending"},
+	)
+
+	err := testProjectIntegrationConnection(context.Background(), client, "42", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "is pending") {
+		t.Errorf("expected the connection test to time out while pending, got %v", err)
+	}
+}
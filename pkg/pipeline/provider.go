@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -40,6 +41,12 @@ func Provider() *schema.Provider {
 				Default:     true,
 				Description: "Toggle for pre-flight checking of Artifactory Enterprise license. Default to `true`.",
 			},
+			"fetch_supported_operating_systems": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fetch the architectures and operating systems supported by the server to validate node pools, instead of the list built into the provider. Default to `false`.",
+			},
 		},
 
 		ResourcesMap: util.AddTelemetry(
@@ -49,13 +56,26 @@ func Provider() *schema.Provider {
 				"pipeline_project_integration": PipelineProjectIntegrationResource(),
 				"pipeline_node_pool":           pipelineNodePoolResource(),
 				"pipeline_node":                pipelineNodeResource(),
+				"pipeline_default_node_pool":   pipelineDefaultNodePoolResource(),
+				"pipeline_static_nodes":        pipelineStaticNodesResource(),
+				"pipeline_run":                 pipelineRunResource(),
 			},
 		),
 
 		DataSourcesMap: util.AddTelemetry(
 			productId,
 			map[string]*schema.Resource{
-				"pipeline_project": projectDataSource(),
+				"pipeline_project":              projectDataSource(),
+				"pipeline_projects":             projectsDataSource(),
+				"pipeline_project_integration":  projectIntegrationDataSource(),
+				"pipeline_project_integrations": projectIntegrationsDataSource(),
+				"pipeline_node_pool":            nodePoolDataSource(),
+				"pipeline_node_pools":           nodePoolsDataSource(),
+				"pipeline_nodes":                nodesDataSource(),
+				"pipeline_pipeline":             pipelineDataSource(),
+				"pipeline_pipelines":            pipelinesDataSource(),
+				"pipeline_run":                  runDataSource(),
+				"pipeline_resource_version":     resourceVersionDataSource(),
 			},
 		),
 	}
@@ -79,14 +99,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVer
 		return nil, diag.Errorf("you must supply a URL")
 	}
 
-	restyBase, err := client.Build(URL.(string), productId)
-	if err != nil {
-		return nil, diag.FromErr(err)
-	}
-
 	accessToken := d.Get("access_token").(string)
 
-	restyBase, err = client.AddAuth(restyBase, "", accessToken)
+	restyBase, err := buildClient(URL.(string), accessToken)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
@@ -99,8 +114,28 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVer
 		}
 	}
 
+	var diags diag.Diagnostics
+	if d.Get("fetch_supported_operating_systems").(bool) {
+		if err := fetchSupportedOperatingSystems(restyBase); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Failed to fetch the supported operating systems",
+				Detail:   fmt.Sprintf("Node pools are validated against the operating systems built into the provider: %s", err),
+			})
+		}
+	}
+
 	featureUsage := fmt.Sprintf("Terraform/%s", terraformVersion)
 	util.SendUsage(ctx, restyBase, productId, featureUsage)
 
-	return restyBase, nil
+	return restyBase, diags
+}
+
+func buildClient(url, accessToken string) (*resty.Client, error) {
+	restyBase, err := client.Build(url, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AddAuth(restyBase, "", accessToken)
 }
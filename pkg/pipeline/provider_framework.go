@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// FrameworkProvider serves the types the SDK can't implement, e.g. ephemeral resources. It is muxed
+// with the SDK provider, see MuxProviderServer.
+type FrameworkProvider struct{}
+
+var _ provider.ProviderWithEphemeralResources = &FrameworkProvider{}
+
+func NewFrameworkProvider() provider.Provider {
+	return &FrameworkProvider{}
+}
+
+func (p *FrameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "pipeline"
+	resp.Version = Version
+}
+
+// Schema mirrors the schema of the SDK provider, the muxed providers must have the same provider schema
+func (p *FrameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	attributes := map[string]fwschema.Attribute{}
+	for name, s := range Provider().Schema {
+		// same as the SDK, a required attribute with a default is optional
+		required, optional := s.Required, s.Optional
+		if required && s.DefaultFunc != nil {
+			if v, err := s.DefaultFunc(); err != nil || v != nil {
+				required, optional = false, true
+			}
+		}
+
+		switch s.Type {
+		case schema.TypeString:
+			attributes[name] = fwschema.StringAttribute{
+				Required:    required,
+				Optional:    optional,
+				Sensitive:   s.Sensitive,
+				Description: s.Description,
+			}
+		case schema.TypeBool:
+			attributes[name] = fwschema.BoolAttribute{
+				Required:    required,
+				Optional:    optional,
+				Sensitive:   s.Sensitive,
+				Description: s.Description,
+			}
+		default:
+			resp.Diagnostics.AddError("Unsupported provider attribute", fmt.Sprintf("%s has an unsupported type %s", name, s.Type))
+		}
+	}
+
+	resp.Schema = fwschema.Schema{Attributes: attributes}
+}
+
+// Configure builds the client from the configuration or the environment, like the SDK provider. The license
+// check and the usage are left to the SDK provider.
+func (p *FrameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var url, accessToken types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("url"), &url)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("access_token"), &accessToken)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sdkSchema := Provider().Schema
+	configValue := func(value types.String, name string) string {
+		if !value.IsNull() && !value.IsUnknown() {
+			return value.ValueString()
+		}
+		if v, err := sdkSchema[name].DefaultValue(); err == nil && v != nil {
+			return v.(string)
+		}
+		return ""
+	}
+
+	client, err := buildClient(configValue(url, "url"), configValue(accessToken, "access_token"))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to build the client", err.Error())
+		return
+	}
+
+	resp.EphemeralResourceData = client
+}
+
+func (p *FrameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *FrameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *FrameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewNodeCredentialsEphemeralResource,
+	}
+}
+
+// MuxProviderServer serves the SDK provider and the framework provider as a single provider
+func MuxProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	providers := []func() tfprotov5.ProviderServer{
+		Provider().GRPCProvider,
+		providerserver.NewProtocol5(NewFrameworkProvider()),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
@@ -4,6 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -18,6 +23,75 @@ func TestProvider_impl(t *testing.T) {
 	var _ = Provider()
 }
 
+func TestMuxProviderServer(t *testing.T) {
+	ctx := context.Background()
+	providerServer, err := MuxProviderServer(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// fails if the provider schemas of the SDK and framework providers differ
+	resp, err := providerServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	if _, ok := resp.EphemeralResourceSchemas["pipeline_node_credentials"]; !ok {
+		t.Error("expected the pipeline_node_credentials ephemeral resource to be served")
+	}
+	if _, ok := resp.ResourceSchemas["pipeline_node"]; !ok {
+		t.Error("expected the pipeline_node resource to be served")
+	}
+}
+
+func TestFrameworkProviderConfigure(t *testing.T) {
+	ctx := context.Background()
+	_, nodeClient := newFakeNodeServer(t, nodeStatusOnline)
+
+	frameworkProvider := NewFrameworkProvider()
+	schemaResp := &provider.SchemaResponse{}
+	frameworkProvider.Schema(ctx, provider.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("err: %v", schemaResp.Diagnostics)
+	}
+
+	// only url and access_token are set, the other attributes are null
+	values := map[string]tftypes.Value{}
+	for name, attribute := range schemaResp.Schema.Attributes {
+		values[name] = tftypes.NewValue(attribute.GetType().TerraformType(ctx), nil)
+	}
+	values["url"] = tftypes.NewValue(tftypes.String, nodeClient.BaseURL)
+	values["access_token"] = tftypes.NewValue(tftypes.String, "test-token")
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), values),
+	}
+
+	configureResp := &provider.ConfigureResponse{}
+	frameworkProvider.Configure(ctx, provider.ConfigureRequest{Config: config}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("err: %v", configureResp.Diagnostics)
+	}
+	client, ok := configureResp.EphemeralResourceData.(*resty.Client)
+	if !ok {
+		t.Fatalf("expected a client for the ephemeral resources, got %T", configureResp.EphemeralResourceData)
+	}
+
+	// the ephemeral resources use the client of the framework provider
+	resp, result := openNodeCredentials(t, client.SetRetryCount(0), 3)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("err: %v", resp.Diagnostics)
+	}
+	if result.Token.ValueString() != "token-1" {
+		t.Errorf("expected the token of the node, got '%s'", result.Token.ValueString())
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	ctx := context.Background()
 	provider, _ := testAccProviders()["pipeline"]()
@@ -0,0 +1,348 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+const systemDefaultNodePoolId = "system"
+
+// defaultNodePoolLock serializes the changes to the default node pool, so resources in the same apply
+// don't switch the default concurrently and record each other as the previous default. It doesn't
+// cover concurrent applies, these are only detected when verifying the default after switching it.
+var defaultNodePoolLock sync.Mutex
+
+func getNodePool(client *resty.Client, id int) (*NodePool, error) {
+	nodePools := []NodePool{}
+	_, err := client.R().
+		SetResult(&nodePools).
+		Get(nodePoolsUrl + "?nodePoolIds=" + strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodePools) == 0 {
+		return nil, nil
+	}
+	return &nodePools[0], nil
+}
+
+// findDefaultNodePools returns the node pools flagged as default in the project, or the system default
+// node pools when projectId is 0. There should be at most one, but the API doesn't enforce it.
+func findDefaultNodePools(client *resty.Client, projectId int) ([]NodePool, error) {
+	req := client.R()
+	if projectId != 0 {
+		req.SetQueryParam("projectIds", strconv.Itoa(projectId))
+	}
+
+	nodePools := []NodePool{}
+	_, err := req.SetResult(&nodePools).Get(nodePoolsUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := []NodePool{}
+	for _, nodePool := range nodePools {
+		if nodePool.ProjectId == projectId && nodePool.IsDefault != nil && *nodePool.IsDefault {
+			defaults = append(defaults, nodePool)
+		}
+	}
+	return defaults, nil
+}
+
+func setNodePoolDefault(client *resty.Client, id int, isDefault bool) error {
+	nodePool, err := getNodePool(client, id)
+	if err != nil {
+		return err
+	}
+	if nodePool == nil {
+		return fmt.Errorf("no node pool found with id %d", id)
+	}
+
+	nodePool.IsDefault = &isDefault
+	_, err = client.R().
+		SetBody(nodePool).
+		Put(nodePoolsUrl + "/" + strconv.Itoa(id))
+	return err
+}
+
+// rollbackDefaultNodePool makes the unset node pools the default again, and unsets the node pool that was made the
+// default, so a failed switch leaves the previous defaults in place
+func rollbackDefaultNodePool(client *resty.Client, nodePoolId int, unsetNodePool bool, unset []int) error {
+	var errs []error
+	for _, id := range unset {
+		if err := setNodePoolDefault(client, id, true); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore the previous default node pool %d: %w", id, err))
+		}
+	}
+	if unsetNodePool {
+		if err := setNodePoolDefault(client, nodePoolId, false); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unset the node pool %d: %w", nodePoolId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// switchDefaultNodePool makes the node pool the default, then unsets the given previous defaults
+// instead of relying on the server to do it, and verifies that the node pool is the only default left.
+// When a previous default can't be unset, the change is rolled back.
+func switchDefaultNodePool(ctx context.Context, client *resty.Client, projectId int, nodePoolId int, previous []NodePool, timeout time.Duration) error {
+	wasDefault := false
+	for _, nodePool := range previous {
+		wasDefault = wasDefault || nodePool.ID == nodePoolId
+	}
+
+	if err := setNodePoolDefault(client, nodePoolId, true); err != nil {
+		return err
+	}
+	var unset []int
+	for _, nodePool := range previous {
+		if nodePool.ID == nodePoolId {
+			continue
+		}
+		if err := setNodePoolDefault(client, nodePool.ID, false); err != nil {
+			err = fmt.Errorf("failed to unset the previous default node pool %d: %w", nodePool.ID, err)
+			return errors.Join(err, rollbackDefaultNodePool(client, nodePoolId, !wasDefault, unset))
+		}
+		unset = append(unset, nodePool.ID)
+	}
+
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		defaults, err := findDefaultNodePools(client, projectId)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		if len(defaults) == 1 && defaults[0].ID == nodePoolId {
+			return nil
+		}
+
+		ids := make([]int, len(defaults))
+		for i, nodePool := range defaults {
+			ids[i] = nodePool.ID
+		}
+		return retry.RetryableError(fmt.Errorf("expected node pool %d to be the only default node pool, found %v. Another apply may be changing the default node pool concurrently", nodePoolId, ids))
+	})
+}
+
+func pipelineDefaultNodePoolResource() *schema.Resource {
+
+	var defaultNodePoolSchema = map[string]*schema.Schema{
+		"node_pool_id": {
+			Type:         schema.TypeInt,
+			Required:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Id of the node pool used by the steps that don't set a node pool.",
+		},
+		"project_id": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			ForceNew:      true,
+			ConflictsWith: []string{"project_key"},
+			ValidateFunc:  validation.IntAtLeast(0),
+			Description:   "Id of the project of the default node pool. Manages the system default node pool when neither `project_id` nor `project_key` is set.",
+		},
+		"project_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Key of the project of the default node pool. Conflicts with `project_id`.",
+		},
+		"on_destroy": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "restore",
+			ValidateFunc: validation.StringInSlice([]string{"restore", "unset"}, false),
+			Description:  "What happens to the default when this resource is destroyed. `restore` makes the previous default node pool the default again, if it still exists. `unset` leaves the project or system without a default node pool. Default to `restore`.",
+		},
+		"previous_node_pool_id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Id of the node pool that was the default before this resource was created. 0 if there was none.",
+		},
+	}
+
+	var readDefaultNodePool = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "readDefaultNodePool")
+
+		projectId := data.Get("project_id").(int)
+		defaults, err := findDefaultNodePools(m.(*resty.Client), projectId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// the default was unset outside of Terraform
+		if len(defaults) == 0 {
+			tflog.Warn(ctx, fmt.Sprintf("no default node pool found for %s, removing from state", data.Id()))
+			data.SetId("")
+			return nil
+		}
+
+		nodePoolId := defaults[0].ID
+		for _, nodePool := range defaults {
+			if nodePool.ID == data.Get("node_pool_id").(int) {
+				nodePoolId = nodePool.ID
+			}
+		}
+
+		var diags diag.Diagnostics
+		if len(defaults) > 1 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Multiple default node pools",
+				Detail:   fmt.Sprintf("%d node pools are flagged as default for %s. The next apply makes node pool %d the only default.", len(defaults), data.Id(), nodePoolId),
+			})
+		}
+
+		return append(diags, diag.FromErr(data.Set("node_pool_id", nodePoolId))...)
+	}
+
+	var createDefaultNodePool = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "createDefaultNodePool")
+		d := &util.ResourceData{ResourceData: data}
+		client := m.(*resty.Client)
+
+		projectId, err := resolveProjectId(d, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		defaultNodePoolLock.Lock()
+		defer defaultNodePoolLock.Unlock()
+
+		previous, err := findDefaultNodePools(client, projectId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		previousId := 0
+		if len(previous) > 0 {
+			previousId = previous[0].ID
+		}
+
+		nodePoolId := d.GetInt("node_pool_id", false)
+		if err := switchDefaultNodePool(ctx, client, projectId, nodePoolId, previous, data.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+
+		if projectId == 0 {
+			data.SetId(systemDefaultNodePoolId)
+		} else {
+			data.SetId(strconv.Itoa(projectId))
+		}
+
+		errs := util.MkLens(data)("project_id", projectId)
+		errs = append(errs, util.MkLens(data)("previous_node_pool_id", previousId)...)
+		if len(errs) > 0 {
+			return diag.Errorf("failed to pack default node pool %q", errs)
+		}
+
+		return readDefaultNodePool(ctx, data, m)
+	}
+
+	var updateDefaultNodePool = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "updateDefaultNodePool")
+
+		if data.HasChange("node_pool_id") {
+			client := m.(*resty.Client)
+			projectId := data.Get("project_id").(int)
+
+			defaultNodePoolLock.Lock()
+			defer defaultNodePoolLock.Unlock()
+
+			previous, err := findDefaultNodePools(client, projectId)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if err := switchDefaultNodePool(ctx, client, projectId, data.Get("node_pool_id").(int), previous, data.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		return readDefaultNodePool(ctx, data, m)
+	}
+
+	var deleteDefaultNodePool = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "deleteDefaultNodePool")
+		client := m.(*resty.Client)
+		projectId := data.Get("project_id").(int)
+
+		defaultNodePoolLock.Lock()
+		defer defaultNodePoolLock.Unlock()
+
+		if previousId := data.Get("previous_node_pool_id").(int); data.Get("on_destroy").(string) == "restore" && previousId != 0 {
+			previous, err := getNodePool(client, previousId)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if previous != nil {
+				current, err := findDefaultNodePools(client, projectId)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+				return diag.FromErr(switchDefaultNodePool(ctx, client, projectId, previousId, current, data.Timeout(schema.TimeoutDelete)))
+			}
+			tflog.Warn(ctx, fmt.Sprintf("previous default node pool %d no longer exists, unsetting the default", previousId))
+		}
+
+		current, err := getNodePool(client, data.Get("node_pool_id").(int))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if current == nil || current.IsDefault == nil || !*current.IsDefault {
+			return nil
+		}
+
+		return diag.FromErr(setNodePoolDefault(client, current.ID, false))
+	}
+
+	var importDefaultNodePool = func(ctx context.Context, data *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		projectId := 0
+		if data.Id() != systemDefaultNodePoolId {
+			var err error
+			projectId, err = strconv.Atoi(data.Id())
+			if err != nil {
+				return nil, fmt.Errorf("expected a project id or '%s', got '%s'", systemDefaultNodePoolId, data.Id())
+			}
+		}
+
+		errs := util.MkLens(data)("project_id", projectId)
+		errs = append(errs, util.MkLens(data)("on_destroy", "restore")...)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to import default node pool %q", errs)
+		}
+
+		return []*schema.ResourceData{data}, nil
+	}
+
+	return &schema.Resource{
+		CreateContext: createDefaultNodePool,
+		ReadContext:   readDefaultNodePool,
+		UpdateContext: updateDefaultNodePool,
+		DeleteContext: deleteDefaultNodePool,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(2 * time.Minute),
+			Update: schema.DefaultTimeout(2 * time.Minute),
+			Delete: schema.DefaultTimeout(2 * time.Minute),
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: importDefaultNodePool,
+		},
+
+		Schema:      defaultNodePoolSchema,
+		Description: "Manages the default node pool of a project, or of the system, used by the steps that don't set a node pool. There can only be one default node pool per project, so only declare one of these resources per project. Changes are serialized within one apply only: after switching the default, the resource verifies that its node pool is the only default left, and fails if a concurrent apply or workspace changed the default in the meantime. A switch that fails to unset a previous default is rolled back.",
+	}
+}
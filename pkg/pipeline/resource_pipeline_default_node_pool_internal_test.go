@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNodePoolsServer serves the node pools of project 1. Updates of the node pools in failing are rejected.
+type fakeNodePoolsServer struct {
+	sync.Mutex
+	defaults map[int]bool
+	failing  map[int]bool
+}
+
+func (f *fakeNodePoolsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/"+nodePoolsUrl+"/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"+nodePoolsUrl+"/"))
+		if f.failing[id] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var nodePool NodePool
+		_ = json.NewDecoder(r.Body).Decode(&nodePool)
+		f.defaults[id] = *nodePool.IsDefault
+	case r.Method == http.MethodGet && r.URL.Path == "/"+nodePoolsUrl:
+		nodePools := []NodePool{}
+		for id, isDefault := range f.defaults {
+			if nodePoolIds := r.URL.Query().Get("nodePoolIds"); nodePoolIds != "" && nodePoolIds != strconv.Itoa(id) {
+				continue
+			}
+			isDefault := isDefault
+			nodePools = append(nodePools, NodePool{ID: id, Name: fmt.Sprintf("pool-%d", id), ProjectId: 1, IsDefault: &isDefault})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(nodePools)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSwitchDefaultNodePool(t *testing.T) {
+	fake := &fakeNodePoolsServer{defaults: map[int]bool{1: true, 2: true, 3: false}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := []NodePool{{ID: 1}, {ID: 2}}
+	if err := switchDefaultNodePool(context.Background(), client.SetRetryCount(0), 1, 3, previous, time.Minute); err != nil {
+		t.Fatalf("expected the default to be switched, got %s", err)
+	}
+	if expected := map[int]bool{1: false, 2: false, 3: true}; fmt.Sprint(fake.defaults) != fmt.Sprint(expected) {
+		t.Errorf("expected the defaults %v, got %v", expected, fake.defaults)
+	}
+}
+
+func TestSwitchDefaultNodePool_rollback(t *testing.T) {
+	fake := &fakeNodePoolsServer{defaults: map[int]bool{1: true, 2: true, 3: false}, failing: map[int]bool{2: true}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := []NodePool{{ID: 1}, {ID: 2}}
+	err = switchDefaultNodePool(context.Background(), client.SetRetryCount(0), 1, 3, previous, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "failed to unset the previous default node pool 2") {
+		t.Fatalf("expected the failure to unset node pool 2 to be reported, got %v", err)
+	}
+	// node pool 1 is the default again, and node pool 3 is unset
+	if expected := map[int]bool{1: true, 2: true, 3: false}; fmt.Sprint(fake.defaults) != fmt.Sprint(expected) {
+		t.Errorf("expected the previous defaults %v to be restored, got %v", expected, fake.defaults)
+	}
+}
@@ -0,0 +1,131 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestAccDefaultNodePool(t *testing.T) {
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	_, fqrn, name := test.MkNames("default-node-pool", "pipeline_default_node_pool")
+
+	const nodePoolsTemplate = `
+		{{ range .nodePools }}
+		resource "pipeline_node_pool" "{{ . }}" {
+			name             = "{{ . }}"
+			project_key      = "{{ $.projectKey }}"
+			number_of_nodes  = 1
+			is_on_demand     = false
+			architecture     = "x86_64"
+			operating_system = "Ubuntu_20.04"
+		}
+		{{ end }}
+	`
+	const defaultNodePoolTemplate = `
+		resource "pipeline_default_node_pool" "{{ .name }}" {
+			project_key  = "{{ .projectKey }}"
+			node_pool_id = pipeline_node_pool.{{ .nodePool }}.id
+		}
+	`
+	previous, first, second := name+"-previous", name+"-first", name+"-second"
+	params := map[string]interface{}{
+		"name":       name,
+		"projectKey": projectKey,
+		"nodePools":  []string{previous, first, second},
+	}
+
+	nodePoolsConfig := util.ExecuteTemplate("TestAccDefaultNodePoolPools", nodePoolsTemplate, params)
+	params["nodePool"] = first
+	firstConfig := nodePoolsConfig + util.ExecuteTemplate("TestAccDefaultNodePoolFirst", defaultNodePoolTemplate, params)
+	params["nodePool"] = second
+	secondConfig := nodePoolsConfig + util.ExecuteTemplate("TestAccDefaultNodePoolSecond", defaultNodePoolTemplate, params)
+
+	nodePoolIds := map[string]int{}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: nodePoolsConfig,
+				Check:  testAccRecordNodePoolIds(nodePoolIds, previous, first, second),
+			},
+			{
+				// the previous default is set outside of Terraform, so restoring it on destroy can be checked
+				PreConfig: func() {
+					acctest.SetNodePoolDefault(t, nodePoolIds[previous], true)
+				},
+				Config: firstConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(fqrn, "node_pool_id", "pipeline_node_pool."+first, "id"),
+					resource.TestCheckResourceAttrPair(fqrn, "previous_node_pool_id", "pipeline_node_pool."+previous, "id"),
+					resource.TestCheckResourceAttr(fqrn, "on_destroy", "restore"),
+					testAccCheckDefaultNodePools(t, nodePoolIds, first, previous, second),
+				),
+			},
+			{
+				Config: secondConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(fqrn, "node_pool_id", "pipeline_node_pool."+second, "id"),
+					resource.TestCheckResourceAttrPair(fqrn, "previous_node_pool_id", "pipeline_node_pool."+previous, "id"),
+					testAccCheckDefaultNodePools(t, nodePoolIds, second, previous, first),
+				),
+			},
+			{
+				ResourceName:            fqrn,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"project_key", "previous_node_pool_id"},
+			},
+			{
+				Config: nodePoolsConfig,
+				Check:  testAccCheckDefaultNodePools(t, nodePoolIds, previous, first, second),
+			},
+		},
+	})
+}
+
+func testAccRecordNodePoolIds(ids map[string]int, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, name := range names {
+			rs, ok := s.RootModule().Resources["pipeline_node_pool."+name]
+			if !ok {
+				return fmt.Errorf("node pool %s not found in state", name)
+			}
+			id, err := strconv.Atoi(rs.Primary.ID)
+			if err != nil {
+				return err
+			}
+			ids[name] = id
+		}
+		return nil
+	}
+}
+
+// testAccCheckDefaultNodePools checks that only the expected node pool is flagged as default by the API
+func testAccCheckDefaultNodePools(t *testing.T, ids map[string]int, expected string, others ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, name := range append([]string{expected}, others...) {
+			nodePool := acctest.GetNodePool(t, ids[name])
+			isDefault := nodePool.IsDefault != nil && *nodePool.IsDefault
+			if isDefault != (name == expected) {
+				return fmt.Errorf("expected isDefault of node pool %s to be %t", name, name == expected)
+			}
+		}
+		return nil
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/jfrog/terraform-provider-shared/util"
@@ -29,11 +31,30 @@ type Node struct {
 	IPAddress         string            `json:"IPAddress,omitempty"`
 	IsSwapEnabled     bool              `json:"isSwapEnabled,omitempty"`
 	SystemPropertyBag SystemPropertyBag `json:"systemPropertyBag,omitempty"`
+	Status            string            `json:"status,omitempty"`
+	LastHeartbeatAt   string            `json:"lastHeartbeatAt,omitempty"`
+	AgentVersion      string            `json:"agentVersion,omitempty"`
+	CurrentStepId     int               `json:"currentStepId,omitempty"`
 	ID                int               `json:"id,omitempty"`
 }
 
 const nodesUrl = "pipelines/api/v1/nodes"
 
+// Platforms of the init scripts of manually initialized nodes
+const (
+	nodeInitPlatformLinux   = "linux"
+	nodeInitPlatformWindows = "windows"
+)
+
+// Status of a node, as reported by the server
+const (
+	nodeStatusInitializing = "initializing"
+	nodeStatusInitialized  = "initialized"
+	nodeStatusOnline       = "online"
+	nodeStatusOffline      = "offline"
+	nodeStatusFailed       = "failed"
+)
+
 func pipelineNodeResource() *schema.Resource {
 
 	var nodeSchema = map[string]*schema.Schema{
@@ -45,9 +66,17 @@ func pipelineNodeResource() *schema.Resource {
 		},
 		"project_id": {
 			Type:         schema.TypeInt,
-			Required:     true,
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"project_id", "project_key"},
 			ValidateFunc: validation.IntAtLeast(0),
-			Description:  "Id of the project where the node will live.",
+			Description:  "Id of the project where the node will live. Exactly one of `project_id` and `project_key` must be set.",
+		},
+		"project_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Key of the project where the node will live. Exactly one of `project_id` and `project_key` must be set.",
 		},
 		"node_pool_id": {
 			Type:         schema.TypeInt,
@@ -58,18 +87,18 @@ func pipelineNodeResource() *schema.Resource {
 		"is_on_demand": {
 			Type:        schema.TypeBool,
 			Required:    true,
-			Description: "Set to true for dynamic node pool. Set to false for static node pool.",
+			Description: "Set to true for dynamic node pool. Set to false for static node pool. Must match `is_on_demand` of the node pool.",
 		},
 		"is_auto_initialized": {
 			Type:        schema.TypeBool,
 			Required:    true,
-			Description: "Determine auto or manual initialization.",
+			Description: "Determine auto or manual initialization. Auto initialization is only available to static node pools.",
 		},
 		"ip_address": {
 			Type:         schema.TypeString,
 			Optional:     true,
 			ValidateFunc: validation.StringIsNotEmpty,
-			Description:  "Node address for auto-initialization.",
+			Description:  "Node address for auto-initialization. Only available to static node pools.",
 		},
 		"is_swap_enabled": {
 			Type:        schema.TypeBool,
@@ -81,14 +110,65 @@ func pipelineNodeResource() *schema.Resource {
 			Computed:  true,
 			Sensitive: true,
 		},
+		"init_script": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "Script initializing the node on Linux, e.g. for `user_data` of a cloud instance or a cloud-init template. Only set when `is_auto_initialized` is `false`. Fetched when the token changes, on create or when the node is reinitialized.",
+		},
+		"init_script_windows": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "PowerShell script initializing the node on Windows. Only set when `is_auto_initialized` is `false`. Fetched when the token changes, on create or when the node is reinitialized.",
+		},
+		"reinitialize_triggers": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Description: "Arbitrary map of values that, when changed, resets the node and rotates its token, e.g. when the VM of the node is rebuilt. The node keeps its Id and node pool. `token` and the init scripts are refreshed.",
+		},
+		"wait_for_initialization": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Wait on create until the node is initialized and online, up to the create timeout. Fails if the node initialization fails. Default to `false`.",
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Status of the node: `initializing`, `initialized`, `online`, `offline` or `failed`.",
+		},
+		"last_heartbeat_at": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Time of the last heartbeat of the node agent.",
+		},
+		"agent_version": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Version of the node agent.",
+		},
+		"current_step_id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Id of the step running on the node. 0 if the node is idle.",
+		},
 	}
 
-	var unpackNode = func(data *schema.ResourceData) (Node, error) {
+	var unpackNode = func(data *schema.ResourceData, m interface{}) (Node, error) {
 		d := &util.ResourceData{ResourceData: data}
 
+		projectId, err := resolveProjectId(d, m)
+		if err != nil {
+			return Node{}, err
+		}
+
 		node := Node{
 			FriendlyName:      d.GetString("friendly_name", false),
-			ProjectId:         d.GetInt("project_id", false),
+			ProjectId:         projectId,
 			NodePoolId:        d.GetInt("node_pool_id", false),
 			IsOnDemand:        d.GetBool("is_on_demand", false),
 			IsAutoInitialized: d.GetBool("is_auto_initialized", false),
@@ -111,7 +191,10 @@ func pipelineNodeResource() *schema.Resource {
 		errors = append(errors, setValue("ip_address", node.IPAddress)...)
 		errors = append(errors, setValue("is_swap_enabled", node.IsSwapEnabled)...)
 		errors = append(errors, setValue("token", node.SystemPropertyBag.Token)...)
-		tflog.Trace(ctx, fmt.Sprintf("token in object: %s", node.SystemPropertyBag.Token))
+		errors = append(errors, setValue("status", node.Status)...)
+		errors = append(errors, setValue("last_heartbeat_at", node.LastHeartbeatAt)...)
+		errors = append(errors, setValue("agent_version", node.AgentVersion)...)
+		errors = append(errors, setValue("current_step_id", node.CurrentStepId)...)
 		if len(errors) > 0 {
 			return diag.Errorf("failed to pack node pool %q", errors)
 		}
@@ -128,15 +211,49 @@ func pipelineNodeResource() *schema.Resource {
 		if err != nil {
 			return diag.FromErr(err)
 		}
-		tflog.Trace(ctx, fmt.Sprintf("from readNode; Node obj %v", node))
-		return packNode(ctx, data, node)
+		tflog.Trace(ctx, fmt.Sprintf("from readNode; Node %d in node pool %d is %s", node.ID, node.NodePoolId, node.Status))
+		previousToken := data.Get("token").(string)
+		if diags := packNode(ctx, data, node); diags.HasError() {
+			return diags
+		}
+
+		if node.IsAutoInitialized {
+			errs := util.MkLens(data)("init_script", "")
+			errs = append(errs, util.MkLens(data)("init_script_windows", "")...)
+			if len(errs) > 0 {
+				return diag.Errorf("failed to pack node %q", errs)
+			}
+			return nil
+		}
+
+		// the init scripts only change with the token, so they are only fetched on create, import or when the node is reinitialized
+		if node.SystemPropertyBag.Token == previousToken && data.Get("init_script").(string) != "" {
+			return nil
+		}
+
+		initScript, initScriptWindows, err := getNodeInitScripts(m.(*resty.Client), data.Id(), node.SystemPropertyBag.Token)
+		if err == nil {
+			errs := util.MkLens(data)("init_script", initScript)
+			errs = append(errs, util.MkLens(data)("init_script_windows", initScriptWindows)...)
+			if len(errs) > 0 {
+				return diag.Errorf("failed to pack node %q", errs)
+			}
+			return nil
+		}
+
+		// keep the init scripts in the state, a failure to fetch them shouldn't block the plans of an existing node
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Failed to fetch the init scripts of the node",
+			Detail:   fmt.Sprintf("init_script and init_script_windows of node %s are not refreshed: %s", data.Id(), err),
+		}}
 	}
 
 	var createNode = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
 		tflog.Debug(ctx, "createNode")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		node, err := unpackNode(data)
+		node, err := unpackNode(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -152,6 +269,12 @@ func pipelineNodeResource() *schema.Resource {
 		}
 		data.SetId(strconv.Itoa(result.ID))
 
+		if data.Get("wait_for_initialization").(bool) {
+			if err := waitForNodeInitialization(ctx, m.(*resty.Client), data.Id(), data.Timeout(schema.TimeoutCreate)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
 		return readNode(ctx, data, m)
 	}
 
@@ -159,16 +282,29 @@ func pipelineNodeResource() *schema.Resource {
 		tflog.Debug(ctx, "updateNode")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		node, err := unpackNode(data)
-		if err != nil {
-			return diag.FromErr(err)
+		// token and the init scripts are only planned as unknown by a change of reinitialize_triggers
+		if data.HasChangesExcept("reinitialize_triggers", "wait_for_initialization", "token", "init_script", "init_script_windows") {
+			node, err := unpackNode(data, m)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			_, err = m.(*resty.Client).R().
+				SetBody(node).
+				Put(nodesUrl + "/" + data.Id())
+			if err != nil {
+				return diag.FromErr(err)
+			}
 		}
 
-		_, err = m.(*resty.Client).R().
-			SetBody(node).
-			Put(nodesUrl + "/" + data.Id())
-		if err != nil {
-			return diag.FromErr(err)
+		// reset after the update, which sends the previous token
+		if data.HasChange("reinitialize_triggers") {
+			tflog.Info(ctx, fmt.Sprintf("reinitializing node %s", data.Id()))
+			_, err := m.(*resty.Client).R().
+				Post(nodesUrl + "/" + data.Id() + "/reset")
+			if err != nil {
+				return diag.FromErr(err)
+			}
 		}
 
 		return readNode(ctx, data, m)
@@ -189,6 +325,64 @@ func pipelineNodeResource() *schema.Resource {
 		return nil
 	}
 
+	var customizeDiff = func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		if diff.Id() != "" && diff.HasChange("reinitialize_triggers") {
+			for _, key := range []string{"token", "init_script", "init_script_windows"} {
+				if err := diff.SetNewComputed(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		// the node pool is created in the same apply
+		if !diff.NewValueKnown("node_pool_id") {
+			return nil
+		}
+
+		client, ok := meta.(*resty.Client)
+		if !ok {
+			return nil
+		}
+		nodePoolId := diff.Get("node_pool_id").(int)
+		nodePool, err := getNodePool(client, nodePoolId)
+		if err != nil {
+			return err
+		}
+		if nodePool == nil {
+			return nil
+		}
+
+		if diff.NewValueKnown("is_on_demand") && diff.Get("is_on_demand").(bool) != nodePool.IsOnDemand {
+			return fmt.Errorf("is_on_demand must match the node pool, node pool %d has is_on_demand set to %t", nodePoolId, nodePool.IsOnDemand)
+		}
+
+		if nodePool.IsOnDemand {
+			if ipAddress := diff.Get("ip_address").(string); ipAddress != "" {
+				return fmt.Errorf("ip_address is only available for nodes in static node pools, node pool %d is dynamic", nodePoolId)
+			}
+			if diff.Get("is_auto_initialized").(bool) {
+				return fmt.Errorf("is_auto_initialized is only available for nodes in static node pools, node pool %d is dynamic", nodePoolId)
+			}
+		}
+
+		if diff.Get("is_swap_enabled").(bool) && isWindowsOperatingSystem(nodePool.OperatingSystem) {
+			return fmt.Errorf("is_swap_enabled is not available for nodes in Windows node pools, node pool %d uses %s", nodePoolId, nodePool.OperatingSystem)
+		}
+
+		// only new nodes, or nodes moving to another node pool, take a slot of the node pool
+		if nodePool.NumberOfNodes > 0 && (diff.Id() == "" || diff.HasChange("node_pool_id")) {
+			nodes, err := getNodePoolNodes(client, nodePoolId)
+			if err != nil {
+				return err
+			}
+			if len(nodes) >= nodePool.NumberOfNodes {
+				return fmt.Errorf("node pool %d already has %d nodes, its number_of_nodes is %d", nodePoolId, len(nodes), nodePool.NumberOfNodes)
+			}
+		}
+
+		return nil
+	}
+
 	return &schema.Resource{
 		SchemaVersion: 1,
 		CreateContext: createNode,
@@ -196,11 +390,70 @@ func pipelineNodeResource() *schema.Resource {
 		UpdateContext: updateNode,
 		DeleteContext: deleteNode,
 
+		CustomizeDiff: customizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+		},
+
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: func(ctx context.Context, data *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if err := data.Set("wait_for_initialization", false); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{data}, nil
+			},
 		},
 
 		Schema:      nodeSchema,
 		Description: "Provides an JFrog Pipelines Node resource.",
 	}
 }
+
+// waitForNodeInitialization polls the node until it is initialized or online, or its initialization failed
+func waitForNodeInitialization(ctx context.Context, client *resty.Client, id string, timeout time.Duration) error {
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		var node Node
+		_, err := client.R().
+			SetResult(&node).
+			Get(nodesUrl + "/" + id)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("node %s is %s", id, node.Status))
+
+		switch node.Status {
+		case nodeStatusInitialized, nodeStatusOnline:
+			return nil
+		case nodeStatusFailed:
+			return retry.NonRetryableError(fmt.Errorf("initialization of node %s failed", id))
+		default:
+			return retry.RetryableError(fmt.Errorf("node %s is %s", id, node.Status))
+		}
+	})
+}
+
+// getNodeInitScript returns the script initializing a manually initialized node, authenticated with the node token
+func getNodeInitScript(client *resty.Client, id, token, platform string) (string, error) {
+	resp, err := client.R().
+		SetAuthToken(token).
+		SetQueryParam("platform", platform).
+		Get(nodesUrl + "/" + id + "/initScript")
+	if err != nil {
+		return "", err
+	}
+	return resp.String(), nil
+}
+
+// getNodeInitScripts returns the Linux and Windows scripts initializing a manually initialized node
+func getNodeInitScripts(client *resty.Client, id, token string) (string, string, error) {
+	initScript, err := getNodeInitScript(client, id, token, nodeInitPlatformLinux)
+	if err != nil {
+		return "", "", err
+	}
+	initScriptWindows, err := getNodeInitScript(client, id, token, nodeInitPlatformWindows)
+	if err != nil {
+		return "", "", err
+	}
+	return initScript, initScriptWindows, nil
+}
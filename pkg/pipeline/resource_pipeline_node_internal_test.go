@@ -0,0 +1,196 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// fakeNodeServer serves node 3, whose status takes the given values in turn, the last one being repeated. A reset
+// rotates the token of the node.
+type fakeNodeServer struct {
+	sync.Mutex
+	node     Node
+	statuses []string
+	polls    int
+	updates  int
+	resets   int
+}
+
+func newFakeNodeServer(t *testing.T, statuses ...string) (*fakeNodeServer, *resty.Client) {
+	fake := &fakeNodeServer{
+		node:     Node{ID: 3, FriendlyName: "node", NodePoolId: 5, SystemPropertyBag: SystemPropertyBag{Token: "token-1"}},
+		statuses: statuses,
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, client.SetRetryCount(0)
+}
+
+func (f *fakeNodeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	nodeUrl := fmt.Sprintf("/%s/%d", nodesUrl, f.node.ID)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == nodeUrl+"/initScript":
+		_, _ = fmt.Fprintf(w, "%s script for %s", r.URL.Query().Get("platform"), strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	case r.Method == http.MethodPost && r.URL.Path == nodeUrl+"/reset":
+		f.resets++
+		f.node.SystemPropertyBag.Token = fmt.Sprintf("token-%d", f.resets+1)
+	case r.Method == http.MethodPut && r.URL.Path == nodeUrl:
+		f.updates++
+	case r.Method == http.MethodGet && r.URL.Path == nodeUrl:
+		f.node.Status = f.statuses[0]
+		if len(f.statuses) > 1 {
+			f.statuses = f.statuses[1:]
+		}
+		f.polls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(f.node)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestWaitForNodeInitialization(t *testing.T) {
+	testCases := map[string]struct {
+		statuses []string
+		polls    int
+	}{
+		"online":      {statuses: []string{nodeStatusInitializing, nodeStatusInitializing, nodeStatusOnline}, polls: 3},
+		"initialized": {statuses: []string{nodeStatusInitialized}, polls: 1},
+	}
+
+	for name, tcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fake, client := newFakeNodeServer(t, tcase.statuses...)
+
+			if err := waitForNodeInitialization(context.Background(), client, "3", time.Minute); err != nil {
+				t.Fatalf("expected the node to be initialized, got %s", err)
+			}
+			if fake.polls != tcase.polls {
+				t.Errorf("expected %d polls, got %d", tcase.polls, fake.polls)
+			}
+		})
+	}
+}
+
+func TestWaitForNodeInitialization_failed(t *testing.T) {
+	fake, client := newFakeNodeServer(t, nodeStatusInitializing, nodeStatusFailed, nodeStatusOnline)
+
+	err := waitForNodeInitialization(context.Background(), client, "3", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "initialization of node 3 failed") {
+		t.Errorf("expected the failed initialization to be reported, got %v", err)
+	}
+	if fake.polls != 2 {
+		t.Errorf("expected the polling to stop when the initialization failed, got %d polls", fake.polls)
+	}
+}
+
+func TestWaitForNodeInitialization_timeout(t *testing.T) {
+	_, client := newFakeNodeServer(t, nodeStatusInitializing)
+
+	err := waitForNodeInitialization(context.Background(), client, "3", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "node 3 is initializing") {
+		t.Errorf("expected a timeout while the node is initializing, got %v", err)
+	}
+}
+
+// nodeState returns the state of manually initialized node 3, created with the given reinitialize_triggers
+func nodeState(t *testing.T, nodeResource *schema.Resource, triggers map[string]interface{}) *terraform.InstanceState {
+	data := nodeResource.TestResourceData()
+	data.SetId("3")
+	for key, value := range map[string]interface{}{
+		"friendly_name":         "node",
+		"project_id":            0,
+		"node_pool_id":          5,
+		"is_on_demand":          false,
+		"is_auto_initialized":   false,
+		"token":                 "token-1",
+		"init_script":           "linux script for token-1",
+		"init_script_windows":   "windows script for token-1",
+		"reinitialize_triggers": triggers,
+	} {
+		if err := data.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return data.State()
+}
+
+// reinitializeNodeDiff plans a change of the reinitialize_triggers of node 3
+func reinitializeNodeDiff(t *testing.T, nodeResource *schema.Resource, state *terraform.InstanceState) *terraform.InstanceDiff {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"friendly_name":         "node",
+		"project_id":            0,
+		"node_pool_id":          5,
+		"is_on_demand":          false,
+		"is_auto_initialized":   false,
+		"reinitialize_triggers": map[string]interface{}{"vm": "2"},
+	})
+	diff, err := nodeResource.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return diff
+}
+
+func TestNodeDiff_reinitializeTriggers(t *testing.T) {
+	nodeResource := pipelineNodeResource()
+	diff := reinitializeNodeDiff(t, nodeResource, nodeState(t, nodeResource, map[string]interface{}{"vm": "1"}))
+
+	if diff.RequiresNew() {
+		t.Error("expected a change of reinitialize_triggers not to replace the node")
+	}
+	for _, key := range []string{"token", "init_script", "init_script_windows"} {
+		if attribute, ok := diff.Attributes[key]; !ok || !attribute.NewComputed {
+			t.Errorf("expected %s to be known after the apply", key)
+		}
+	}
+}
+
+func TestNodeUpdate_reinitializeTriggers(t *testing.T) {
+	fake, client := newFakeNodeServer(t, nodeStatusOnline)
+
+	nodeResource := pipelineNodeResource()
+	state := nodeState(t, nodeResource, map[string]interface{}{"vm": "1"})
+	data, err := schema.InternalMap(nodeResource.Schema).Data(state, reinitializeNodeDiff(t, nodeResource, state))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := nodeResource.UpdateContext(context.Background(), data, client); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	if fake.resets != 1 {
+		t.Errorf("expected the node to be reset once, got %d resets", fake.resets)
+	}
+	if fake.updates != 0 {
+		t.Errorf("expected the node not to be updated when only reinitialize_triggers changed, got %d updates", fake.updates)
+	}
+	if data.Id() != "3" {
+		t.Errorf("expected the node to keep its Id, got '%s'", data.Id())
+	}
+	if token := data.Get("token").(string); token != "token-2" {
+		t.Errorf("expected the rotated token, got '%s'", token)
+	}
+	if initScript := data.Get("init_script").(string); initScript != "linux script for token-2" {
+		t.Errorf("expected the init script of the rotated token, got '%s'", initScript)
+	}
+}
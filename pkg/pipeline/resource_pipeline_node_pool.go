@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/jfrog/terraform-provider-shared/util"
@@ -27,14 +29,35 @@ type NodePool struct {
 	OperatingSystem        string   `json:"operatingSystem"`
 	NodeIdleIntervalInMins int      `json:"nodeIdleIntervalInMins"`
 	Environments           []string `json:"environments,omitempty"`
-	ID                     int      `json:"id,omitempty"`
+	// IsDefault is only sent by pipeline_default_node_pool, so updating a node pool doesn't change the default
+	IsDefault *bool `json:"isDefault,omitempty"`
+	// IsDraining is only sent when draining the node pool before deleting it
+	IsDraining *bool `json:"isDraining,omitempty"`
+	ID         int   `json:"id,omitempty"`
+
+	ProviderSettings *NodePoolProviderSettings `json:"providerSettings,omitempty"`
+	Runtime          *NodePoolRuntime          `json:"runtime,omitempty"`
+}
+
+type NodePoolRuntime struct {
+	Type                 string            `json:"type,omitempty"`
+	ImageRegistry        string            `json:"imageRegistry,omitempty"`
+	ImageRepository      string            `json:"imageRepository,omitempty"`
+	LanguageVersions     map[string]string `json:"languageVersions,omitempty"`
+	EnvironmentVariables map[string]string `json:"environmentVariables,omitempty"`
 }
 
 const nodePoolsUrl = "pipelines/api/v1/nodePools"
 
+const (
+	nodePoolDeleteFail  = "fail"
+	nodePoolDeleteDrain = "drain"
+	nodePoolDeleteForce = "force"
+)
+
 func pipelineNodePoolResource() *schema.Resource {
 
-	var nodePoolSchema = map[string]*schema.Schema{
+	var nodePoolSchema = util.MergeMaps(nodePoolProviderSchema(), map[string]*schema.Schema{
 		"name": {
 			Type:         schema.TypeString,
 			Required:     true,
@@ -43,15 +66,23 @@ func pipelineNodePoolResource() *schema.Resource {
 		},
 		"project_id": {
 			Type:         schema.TypeInt,
-			Required:     true,
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"project_id", "project_key"},
 			ValidateFunc: validation.IntAtLeast(0),
-			Description:  "Id of the project where the node pool will live.",
+			Description:  "Id of the project where the node pool will live. Exactly one of `project_id` and `project_key` must be set.",
+		},
+		"project_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Key of the project where the node pool will live. Exactly one of `project_id` and `project_key` must be set.",
 		},
 		"number_of_nodes": {
 			Type:         schema.TypeInt,
 			Optional:     true,
 			ValidateFunc: validation.IntAtLeast(0),
-			Description:  "Max number of nodes available in the pool.",
+			Description:  "Max number of nodes available in the pool. The plan of a `pipeline_node` fails when the node pool is already full, but each new node is only checked against the nodes that exist, so several new nodes of the same plan can exceed the limit. The check reads the nodes of the node pool once per planned node.",
 		},
 		"is_on_demand": {
 			Type:        schema.TypeBool,
@@ -62,13 +93,13 @@ func pipelineNodePoolResource() *schema.Resource {
 			Type:         schema.TypeString,
 			Required:     true,
 			ValidateFunc: validation.StringIsNotEmpty,
-			Description:  "Set the architecture. This is currently limited to x86_64.",
+			Description:  "Set the architecture, `x86_64` or `ARM64`.",
 		},
 		"operating_system": {
 			Type:         schema.TypeString,
 			Required:     true,
 			ValidateFunc: validation.StringIsNotEmpty,
-			Description:  "Operating systems supported for the selected architecture.",
+			Description:  "Operating systems supported for the selected architecture, e.g. `Ubuntu_20.04`, `CentOS_7`, `RHEL_8` or `WindowsServer_2019`. Windows Server is only supported on `x86_64`. Validated against the supported operating systems when the node pool is created or the value changes.",
 		},
 		"node_idle_interval_in_mins": {
 			Type:         schema.TypeInt,
@@ -84,13 +115,73 @@ func pipelineNodePoolResource() *schema.Resource {
 			},
 			Description: "In a project, an array of environment names in which this pipeline source will be.",
 		},
-	}
+		"delete_behavior": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      nodePoolDeleteForce,
+			ValidateFunc: validation.StringInSlice([]string{nodePoolDeleteFail, nodePoolDeleteDrain, nodePoolDeleteForce}, false),
+			Description:  "What happens on destroy when the node pool is in use. `fail` refuses to delete a node pool with nodes or active steps. `drain` stops scheduling new steps on the node pool, waits for the running steps up to the delete timeout, then deletes it. It requires a server that reports the node pool as draining, destroy fails otherwise. `force` deletes it right away. Default to `force`.",
+		},
+		"runtime": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringInSlice([]string{"container", "host"}, false),
+						Description:  "Default runtime of the steps: `container` or `host`.",
+					},
+					"image_registry": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringIsNotEmpty,
+						Description:  "Registry of the default build image, e.g. `releases-docker.jfrog.io`. Set by the server when not configured.",
+					},
+					"image_repository": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.StringIsNotEmpty,
+						Description:  "Repository of the default build image, e.g. `jfrog/pipelines-u20node`. Set by the server when not configured.",
+					},
+					"language_versions": {
+						Type:     schema.TypeMap,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+						Description: "Default version of each language or runtime, keyed by language, e.g. `{ node = \"18\", java = \"17\" }`.",
+					},
+					"environment_variables": {
+						Type:     schema.TypeMap,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+						Description: "Environment variables set for every step running on the node pool.",
+					},
+				},
+			},
+			Description: "Runtime settings of the steps running on the node pool. Left to the server defaults when not set.",
+		},
+	})
 
-	var unpackNodePool = func(data *schema.ResourceData) (NodePool, error) {
+	var unpackNodePool = func(data *schema.ResourceData, m interface{}) (NodePool, error) {
 		d := &util.ResourceData{ResourceData: data}
 
+		projectId, err := resolveProjectId(d, m)
+		if err != nil {
+			return NodePool{}, err
+		}
+
 		nodePool := NodePool{
-			ProjectId:              d.GetInt("project_id", false),
+			ProjectId:              projectId,
 			Name:                   d.GetString("name", false),
 			NumberOfNodes:          d.GetInt("number_of_nodes", false),
 			IsOnDemand:             d.GetBool("is_on_demand", false),
@@ -98,30 +189,12 @@ func pipelineNodePoolResource() *schema.Resource {
 			OperatingSystem:        d.GetString("operating_system", false),
 			NodeIdleIntervalInMins: d.GetInt("node_idle_interval_in_mins", false),
 			Environments:           d.GetList("environments"),
+			ProviderSettings:       unpackNodePoolProviderSettings(d),
+			Runtime:                unpackNodePoolRuntime(d),
 		}
 		return nodePool, nil
 	}
 
-	var packNodePool = func(d *schema.ResourceData, nodePool NodePool) diag.Diagnostics {
-		var errors []error
-		setValue := util.MkLens(d)
-
-		errors = setValue("project_id", nodePool.ProjectId)
-		errors = append(errors, setValue("name", nodePool.Name)...)
-		errors = append(errors, setValue("number_of_nodes", nodePool.NumberOfNodes)...)
-		errors = append(errors, setValue("is_on_demand", nodePool.IsOnDemand)...)
-		errors = append(errors, setValue("architecture", nodePool.Architecture)...)
-		errors = append(errors, setValue("operating_system", nodePool.OperatingSystem)...)
-		errors = append(errors, setValue("node_idle_interval_in_mins", nodePool.NodeIdleIntervalInMins)...)
-		errors = append(errors, setValue("environments", nodePool.Environments)...)
-
-		if len(errors) > 0 {
-			return diag.Errorf("failed to pack node pool %q", errors)
-		}
-
-		return nil
-	}
-
 	var readNodePool = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
 		tflog.Debug(ctx, "readNodePool")
 
@@ -143,7 +216,7 @@ func pipelineNodePoolResource() *schema.Resource {
 		tflog.Debug(ctx, "createNodePool")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		nodePool, err := unpackNodePool(data)
+		nodePool, err := unpackNodePool(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -167,7 +240,7 @@ func pipelineNodePoolResource() *schema.Resource {
 		tflog.Debug(ctx, "updateNodePool")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		nodePool, err := unpackNodePool(data)
+		nodePool, err := unpackNodePool(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -186,6 +259,22 @@ func pipelineNodePoolResource() *schema.Resource {
 		tflog.Debug(ctx, "deleteNodePool")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
+		nodePoolId, err := strconv.Atoi(data.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		switch data.Get("delete_behavior").(string) {
+		case nodePoolDeleteFail:
+			if err := checkNodePoolUnused(m.(*resty.Client), nodePoolId); err != nil {
+				return diag.FromErr(err)
+			}
+		case nodePoolDeleteDrain:
+			if err := drainNodePool(ctx, m.(*resty.Client), nodePoolId, data.Timeout(schema.TimeoutDelete)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
 		resp, err := m.(*resty.Client).R().
 			Delete(nodePoolsUrl + "/" + data.Id())
 
@@ -197,18 +286,189 @@ func pipelineNodePoolResource() *schema.Resource {
 		return nil
 	}
 
+	var customizeDiff = func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		// only new or changed values are validated, so existing node pools with an operating system missing from the
+		// list, e.g. one newly supported by the server, keep planning after an upgrade of the provider
+		changed := diff.Id() == "" || diff.HasChanges("architecture", "operating_system")
+		if changed && diff.NewValueKnown("architecture") && diff.NewValueKnown("operating_system") {
+			client, _ := meta.(*resty.Client)
+			if err := validateNodePoolOperatingSystem(client, diff.Get("architecture").(string), diff.Get("operating_system").(string)); err != nil {
+				return err
+			}
+		}
+
+		for _, block := range nodePoolProviderBlocks {
+			blocks := diff.Get(block).([]interface{})
+			if len(blocks) == 0 || blocks[0] == nil {
+				continue
+			}
+
+			if diff.NewValueKnown("is_on_demand") && !diff.Get("is_on_demand").(bool) {
+				return fmt.Errorf("%s can only be set for a dynamic node pool, with is_on_demand set to true", block)
+			}
+
+			values := blocks[0].(map[string]interface{})
+			minNodes, maxNodes := values["min_nodes"].(int), values["max_nodes"].(int)
+			if maxNodes > 0 && minNodes > maxNodes {
+				return fmt.Errorf("%s.0.min_nodes (%d) can not be greater than %s.0.max_nodes (%d)", block, minNodes, block, maxNodes)
+			}
+		}
+
+		return nil
+	}
+
 	return &schema.Resource{
 		SchemaVersion: 1,
 		CreateContext: createNodePool,
 		ReadContext:   readNodePool,
 		UpdateContext: updateNodePool,
 		DeleteContext: deleteNodePool,
+		CustomizeDiff: customizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: func(ctx context.Context, data *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				if err := data.Set("delete_behavior", nodePoolDeleteForce); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{data}, nil
+			},
 		},
 
 		Schema:      nodePoolSchema,
 		Description: "Provides an Jfrog Pipelines Node Pool resource.",
 	}
 }
+
+// getActiveStepCount returns the number of steps waiting or running on the node pool
+func getActiveStepCount(client *resty.Client, nodePoolId int) (int, error) {
+	steps := []json.RawMessage{}
+	_, err := client.R().
+		SetQueryParams(map[string]string{
+			"nodePoolIds": strconv.Itoa(nodePoolId),
+			"statusCodes": activeStepStatusCodes,
+		}).
+		SetResult(&steps).
+		Get(stepsUrl)
+	return len(steps), err
+}
+
+func checkNodePoolUnused(client *resty.Client, nodePoolId int) error {
+	nodes, err := getNodePoolNodes(client, nodePoolId)
+	if err != nil {
+		return err
+	}
+	if len(nodes) > 0 {
+		return fmt.Errorf("node pool %d still has %d nodes, delete them first or set delete_behavior to drain or force", nodePoolId, len(nodes))
+	}
+
+	activeSteps, err := getActiveStepCount(client, nodePoolId)
+	if err != nil {
+		return err
+	}
+	if activeSteps > 0 {
+		return fmt.Errorf("node pool %d still has %d active steps, wait for them to complete or set delete_behavior to drain or force", nodePoolId, activeSteps)
+	}
+
+	return nil
+}
+
+// drainNodePool stops scheduling new steps on the node pool and waits for the active steps to complete
+func drainNodePool(ctx context.Context, client *resty.Client, nodePoolId int, timeout time.Duration) error {
+	nodePool, err := getNodePool(client, nodePoolId)
+	if err != nil {
+		return err
+	}
+	if nodePool == nil {
+		return nil
+	}
+
+	isDraining := true
+	nodePool.IsDraining = &isDraining
+	_, err = client.R().
+		SetBody(nodePool).
+		Put(nodePoolsUrl + "/" + strconv.Itoa(nodePoolId))
+	if err != nil {
+		return err
+	}
+
+	// a server without drain support ignores isDraining and keeps scheduling steps on the node pool, so the
+	// flag is read back rather than trusting the PUT
+	nodePool, err = getNodePool(client, nodePoolId)
+	if err != nil {
+		return err
+	}
+	if nodePool != nil && (nodePool.IsDraining == nil || !*nodePool.IsDraining) {
+		return fmt.Errorf("node pool %d wasn't marked as draining by the server, which may not support draining node pools, set delete_behavior to fail or force", nodePoolId)
+	}
+
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		activeSteps, err := getActiveStepCount(client, nodePoolId)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		tflog.Debug(ctx, fmt.Sprintf("draining node pool %d: %d active steps", nodePoolId, activeSteps))
+
+		if activeSteps > 0 {
+			return retry.RetryableError(fmt.Errorf("node pool %d still has %d active steps", nodePoolId, activeSteps))
+		}
+		return nil
+	})
+}
+
+func packNodePool(d *schema.ResourceData, nodePool NodePool) diag.Diagnostics {
+	var errors []error
+	setValue := util.MkLens(d)
+
+	errors = setValue("project_id", nodePool.ProjectId)
+	errors = append(errors, setValue("name", nodePool.Name)...)
+	errors = append(errors, setValue("number_of_nodes", nodePool.NumberOfNodes)...)
+	errors = append(errors, setValue("is_on_demand", nodePool.IsOnDemand)...)
+	errors = append(errors, setValue("architecture", nodePool.Architecture)...)
+	errors = append(errors, setValue("operating_system", nodePool.OperatingSystem)...)
+	errors = append(errors, setValue("node_idle_interval_in_mins", nodePool.NodeIdleIntervalInMins)...)
+	errors = append(errors, setValue("environments", nodePool.Environments)...)
+	errors = append(errors, packNodePoolProviderSettings(setValue, nodePool.ProviderSettings)...)
+	errors = append(errors, setValue("runtime", packNodePoolRuntime(nodePool.Runtime))...)
+
+	if len(errors) > 0 {
+		return diag.Errorf("failed to pack node pool %q", errors)
+	}
+
+	return nil
+}
+
+func unpackNodePoolRuntime(d *util.ResourceData) *NodePoolRuntime {
+	runtimes := d.Get("runtime").([]interface{})
+	if len(runtimes) == 0 || runtimes[0] == nil {
+		return nil
+	}
+	values := runtimes[0].(map[string]interface{})
+
+	return &NodePoolRuntime{
+		Type:                 values["type"].(string),
+		ImageRegistry:        values["image_registry"].(string),
+		ImageRepository:      values["image_repository"].(string),
+		LanguageVersions:     castToStringMap(values["language_versions"].(map[string]interface{})),
+		EnvironmentVariables: castToStringMap(values["environment_variables"].(map[string]interface{})),
+	}
+}
+
+func packNodePoolRuntime(runtime *NodePoolRuntime) []interface{} {
+	if runtime == nil {
+		return nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"type":                  runtime.Type,
+			"image_registry":        runtime.ImageRegistry,
+			"image_repository":      runtime.ImageRepository,
+			"language_versions":     runtime.LanguageVersions,
+			"environment_variables": runtime.EnvironmentVariables,
+		},
+	}
+}
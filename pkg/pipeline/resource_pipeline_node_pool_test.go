@@ -0,0 +1,163 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestNodePoolRuntime(t *testing.T) {
+	runtime := &NodePoolRuntime{
+		Type:                 "container",
+		ImageRegistry:        "releases-docker.jfrog.io",
+		ImageRepository:      "jfrog/pipelines-u20node",
+		LanguageVersions:     map[string]string{"node": "18"},
+		EnvironmentVariables: map[string]string{"CI": "true"},
+	}
+
+	data := pipelineNodePoolResource().TestResourceData()
+	if err := data.Set("runtime", packNodePoolRuntime(runtime)); err != nil {
+		t.Fatalf("error packing runtime %v", err)
+	}
+
+	roundTrip := unpackNodePoolRuntime(&util.ResourceData{ResourceData: data})
+	if !reflect.DeepEqual(runtime, roundTrip) {
+		t.Errorf("round trip returned %+v; expected %+v", roundTrip, runtime)
+	}
+
+	if err := data.Set("runtime", packNodePoolRuntime(nil)); err != nil {
+		t.Fatalf("error packing runtime %v", err)
+	}
+	if roundTrip := unpackNodePoolRuntime(&util.ResourceData{ResourceData: data}); roundTrip != nil {
+		t.Errorf("expected no runtime, got %+v", roundTrip)
+	}
+}
+
+// fakeNodePoolServer serves node pool 5 with its nodes and active steps, and keeps isDraining when it supports draining
+type fakeNodePoolServer struct {
+	sync.Mutex
+	nodePool      NodePool
+	nodes         []Node
+	activeSteps   []int
+	supportsDrain bool
+	deleted       bool
+}
+
+func newFakeNodePoolServer(t *testing.T, fake *fakeNodePoolServer) *resty.Client {
+	fake.nodePool = NodePool{ID: 5, Name: "pool"}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.SetRetryCount(0)
+}
+
+func (f *fakeNodePoolServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.URL.Path == "/"+nodesUrl:
+		_ = json.NewEncoder(w).Encode(f.nodes)
+	case r.URL.Path == "/"+stepsUrl:
+		// each poll sees the next count of active steps, the last one being repeated
+		count := f.activeSteps[0]
+		if len(f.activeSteps) > 1 {
+			f.activeSteps = f.activeSteps[1:]
+		}
+		_ = json.NewEncoder(w).Encode(make([]json.RawMessage, count))
+	case r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode([]NodePool{f.nodePool})
+	case r.Method == http.MethodPut:
+		var nodePool NodePool
+		_ = json.NewDecoder(r.Body).Decode(&nodePool)
+		if !f.supportsDrain {
+			nodePool.IsDraining = nil
+		}
+		f.nodePool = nodePool
+	case r.Method == http.MethodDelete:
+		f.deleted = true
+	}
+}
+
+func TestCheckNodePoolUnused(t *testing.T) {
+	testCases := map[string]struct {
+		nodes       []Node
+		activeSteps int
+		expected    string
+	}{
+		"unused":       {},
+		"nodes":        {nodes: []Node{{ID: 1, NodePoolId: 5}}, activeSteps: 1, expected: "still has 1 nodes"},
+		"active steps": {activeSteps: 2, expected: "still has 2 active steps"},
+	}
+
+	for name, tcase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeNodePoolServer(t, &fakeNodePoolServer{nodes: tcase.nodes, activeSteps: []int{tcase.activeSteps}})
+
+			err := checkNodePoolUnused(client, 5)
+			if tcase.expected == "" && err != nil {
+				t.Errorf("expected the node pool to be unused, got %s", err)
+			}
+			if tcase.expected != "" && (err == nil || !strings.Contains(err.Error(), tcase.expected)) {
+				t.Errorf("expected an error containing '%s', got %v", tcase.expected, err)
+			}
+		})
+	}
+}
+
+func TestDeleteNodePool_failWithNodes(t *testing.T) {
+	fake := &fakeNodePoolServer{nodes: []Node{{ID: 1, NodePoolId: 5}}, activeSteps: []int{0}}
+	client := newFakeNodePoolServer(t, fake)
+
+	nodePoolResource := pipelineNodePoolResource()
+	data := nodePoolResource.TestResourceData()
+	data.SetId("5")
+	if err := data.Set("delete_behavior", nodePoolDeleteFail); err != nil {
+		t.Fatal(err)
+	}
+
+	if diags := nodePoolResource.DeleteContext(context.Background(), data, client); !diags.HasError() {
+		t.Error("expected the delete to fail while the node pool has nodes")
+	}
+	if fake.deleted {
+		t.Error("expected the node pool not to be deleted")
+	}
+}
+
+func TestDrainNodePool(t *testing.T) {
+	fake := &fakeNodePoolServer{activeSteps: []int{2, 1, 0}, supportsDrain: true}
+	client := newFakeNodePoolServer(t, fake)
+
+	if err := drainNodePool(context.Background(), client, 5, time.Minute); err != nil {
+		t.Fatalf("expected the node pool to be drained, got %s", err)
+	}
+	if fake.nodePool.IsDraining == nil || !*fake.nodePool.IsDraining {
+		t.Error("expected the node pool to be marked as draining")
+	}
+	if len(fake.activeSteps) != 1 {
+		t.Errorf("expected the active steps to be polled until there were none, %d polls left", len(fake.activeSteps)-1)
+	}
+}
+
+func TestDrainNodePool_unsupported(t *testing.T) {
+	client := newFakeNodePoolServer(t, &fakeNodePoolServer{activeSteps: []int{0}})
+
+	err := drainNodePool(context.Background(), client, 5, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "wasn't marked as draining") {
+		t.Errorf("expected an error when the server ignores isDraining, got %v", err)
+	}
+}
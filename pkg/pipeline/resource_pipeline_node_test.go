@@ -0,0 +1,59 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+func TestAccNode_nodePoolMismatch(t *testing.T) {
+	projectKey := fmt.Sprintf("t%d", test.RandomInt())
+	_, _, name := test.MkNames("node", "pipeline_node")
+
+	config := util.ExecuteTemplate("TestAccNodeNodePoolMismatchConfig", `
+		resource "pipeline_node_pool" "{{ .name }}" {
+			name             = "{{ .name }}"
+			project_key      = "{{ .projectKey }}"
+			number_of_nodes  = 1
+			is_on_demand     = false
+			architecture     = "x86_64"
+			operating_system = "Ubuntu_20.04"
+		}
+
+		resource "pipeline_node" "{{ .name }}" {
+			friendly_name       = "{{ .name }}"
+			project_key         = "{{ .projectKey }}"
+			node_pool_id        = pipeline_node_pool.{{ .name }}.id
+			is_on_demand        = true
+			is_auto_initialized = false
+		}
+	`, map[string]interface{}{
+		"name":       name,
+		"projectKey": projectKey,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.CreateProject(t, projectKey)
+		},
+		CheckDestroy: func(*terraform.State) error {
+			acctest.DeleteProject(t, projectKey)
+			return nil
+		},
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the node pool id is only known during apply, when the diff is computed again
+				Config:      config,
+				ExpectError: regexp.MustCompile(`is_on_demand must match the node pool`),
+			},
+		},
+	})
+}
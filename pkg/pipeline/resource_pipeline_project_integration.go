@@ -2,12 +2,18 @@ package pipeline
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -26,6 +32,7 @@ type ProjectIntegration struct {
 	FormJSONValues        []FormJSONValues `json:"formJSONValues"`
 	Environments          []string         `json:"environments,omitempty"`
 	IsInternal            bool             `json:"isInternal,omitempty"`
+	UpdatedAt             string           `json:"updatedAt,omitempty"`
 	ID                    int              `json:"id,omitempty"`
 }
 
@@ -39,6 +46,13 @@ func (f FormJSONValues) Id() string {
 	return f.Label
 }
 
+// RedactedFormJSONValue is returned by the API in place of a sensitive value
+const RedactedFormJSONValue = "********"
+
+// ChangedSensitiveValuesKey is stored in sensitive_value_hashes when the integration was changed outside of
+// Terraform, so the next plan sends the sensitive values again, including the write-only ones
+const ChangedSensitiveValuesKey = "changed_outside_terraform"
+
 type ProjectJSON struct {
 	Key  string `json:"key,omitempty"`
 	Name string `json:"name,omitempty"`
@@ -46,6 +60,27 @@ type ProjectJSON struct {
 
 const projectIntegrationsUrl = "pipelines/api/v1/projectintegrations"
 
+var formJSONValuesElem = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"label": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Key or label of the input property.",
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Value of the input property.",
+		},
+		"is_sensitive": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Is the underlying Value sensitive or not",
+		},
+	},
+}
+
 var baseProjectIntegrationSchema = map[string]*schema.Schema{
 	"name": {
 		Type:         schema.TypeString,
@@ -74,28 +109,9 @@ var baseProjectIntegrationSchema = map[string]*schema.Schema{
 		Description:  "The name of the master integration.",
 	},
 	"form_json_values": {
-		Type:     schema.TypeList,
-		Required: true,
-		Elem: &schema.Resource{
-			Schema: map[string]*schema.Schema{
-				"label": {
-					Type:        schema.TypeString,
-					Required:    true,
-					Description: "Key or label of the input property.",
-				},
-				"value": {
-					Type:        schema.TypeString,
-					Required:    true,
-					Description: "Value of the input property.",
-				},
-				"is_sensitive": {
-					Type:        schema.TypeBool,
-					Optional:    true,
-					Default:     false,
-					Description: "Is the underlying Value sensitive or not",
-				},
-			},
-		},
+		Type:        schema.TypeList,
+		Required:    true,
+		Elem:        formJSONValuesElem,
 		Description: "Multiple objects with the values for the integration.",
 	},
 	"environments": {
@@ -157,6 +173,84 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		},
 	)
 
+	var projectIntegrationSchemaV3 = util.MergeMaps(
+		projectIntegrationSchemaV2,
+		map[string]*schema.Schema{
+			"form_json_values": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     formJSONValuesElem,
+				// values are keyed by label, so reordering the blocks or the API response doesn't cause a diff
+				Set: func(v interface{}) int {
+					return schema.HashString(v.(map[string]interface{})["label"])
+				},
+				Description: "Multiple objects with the values for the integration. Each label can only be set once.",
+			},
+			"project_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"project_id", "project"},
+				ValidateFunc:  validation.StringIsNotEmpty,
+				Description:   "Key of the project, as an alternative to `project_id` and `project`. Conflicts with both of them.",
+			},
+			"secret_form_json_values": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "Key or label of the input property.",
+						},
+						"value_wo": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							WriteOnly:   true,
+							Description: "Value of the input property. This value is sent to the API but never stored in the Terraform state. Requires Terraform 1.11 or later.",
+						},
+					},
+				},
+				Description: "Multiple objects with write-only values for the integration, such as tokens or passwords. Change `secret_version` to send updated values.",
+			},
+			"secret_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Version of the values in `secret_form_json_values`. Change this value to send the write-only values to the API again.",
+			},
+			"sensitive_value_hashes": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Sensitive:   true,
+				Description: "Salted hashes of the sensitive values, keyed by label. Used to detect changes to sensitive values, which the API always returns redacted.",
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the last update of the integration, as reported by the API. A change made outside of Terraform causes the sensitive values to be sent again.",
+			},
+			"validate_connection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Test the connection of the integration, e.g. the token or URL, after it is created or updated. The apply fails with the message from the server if the connection is rejected. Default to `false`.",
+			},
+			"on_connection_failure": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      connectionFailureTaint,
+				ValidateFunc: validation.StringInSlice([]string{connectionFailureTaint, connectionFailureDelete}, false),
+				Description:  "What happens to a new integration when its connection test fails, with `validate_connection` set. `taint` keeps the integration in the state as tainted, so the next apply replaces it. `delete` deletes the integration, so the failed apply leaves nothing behind. An updated integration is always kept with its new values. Default to `taint`.",
+			},
+		},
+	)
+
 	var unpackProject = func(d *util.ResourceData) ProjectJSON {
 		var project ProjectJSON
 		if v, ok := d.GetOk("project"); ok {
@@ -172,18 +266,31 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		return project
 	}
 
-	var unpackProjectIntegration = func(data *schema.ResourceData) (ProjectIntegration, error) {
+	var unpackProjectIntegration = func(data *schema.ResourceData, m interface{}) (ProjectIntegration, error) {
 		d := &util.ResourceData{ResourceData: data}
 
+		projectId, err := resolveProjectId(d, m)
+		if err != nil {
+			return ProjectIntegration{}, err
+		}
+
+		formJSONValues := UnpackFormJSONValues(d, "form_json_values")
+		secretFormJSONValues := UnpackSecretFormJSONValues(data.GetRawConfig(), "secret_form_json_values")
+		for _, secret := range secretFormJSONValues {
+			if FindConfigurationById(formJSONValues, secret.Label) != nil {
+				return ProjectIntegration{}, fmt.Errorf("label %q is set in both form_json_values and secret_form_json_values", secret.Label)
+			}
+		}
+
 		projectIntegration := ProjectIntegration{
 			Name:                  d.GetString("name", false),
-			ProjectId:             d.GetInt("project_id", false),
+			ProjectId:             projectId,
 			MasterIntegrationId:   d.GetInt("master_integration_id", false),
 			MasterIntegrationName: d.GetString("master_integration_name", false),
 			Environments:          d.GetList("environments"),
 			IsInternal:            d.GetBool("is_internal", false),
 			Project:               unpackProject(d),
-			FormJSONValues:        UnpackFormJSONValues(d, "form_json_values"),
+			FormJSONValues:        append(formJSONValues, secretFormJSONValues...),
 		}
 		return projectIntegration, nil
 	}
@@ -197,7 +304,19 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		setValue("master_integration_name", projectIntegration.MasterIntegrationName)
 		setValue("environments", projectIntegration.Environments)
 		setValue("is_internal", projectIntegration.IsInternal)
-		errors := PackFormJSONValues(ctx, d, "form_json_values", projectIntegration.FormJSONValues)
+
+		// values supplied through secret_form_json_values are write-only and must stay out of the state
+		secretLabels := map[string]bool{}
+		for _, secret := range d.Get("secret_form_json_values").([]interface{}) {
+			secretLabels[secret.(map[string]interface{})["label"].(string)] = true
+		}
+		var formJSONValues []FormJSONValues
+		for _, formJSONValue := range projectIntegration.FormJSONValues {
+			if !secretLabels[formJSONValue.Label] {
+				formJSONValues = append(formJSONValues, formJSONValue)
+			}
+		}
+		errors := PackFormJSONValues(ctx, d, "form_json_values", formJSONValues)
 
 		if len(errors) > 0 {
 			return diag.Errorf("failed to pack project integration %q", errors)
@@ -206,6 +325,22 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		return nil
 	}
 
+	var packSensitiveValueHashes = func(d *schema.ResourceData, projectIntegration ProjectIntegration) diag.Diagnostics {
+		hashes := map[string]interface{}{}
+		for _, formJSONValue := range projectIntegration.FormJSONValues {
+			if !formJSONValue.Sensitive {
+				continue
+			}
+			hash, err := HashSensitiveValue(formJSONValue.Value)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			hashes[formJSONValue.Label] = hash
+		}
+
+		return diag.FromErr(d.Set("sensitive_value_hashes", hashes))
+	}
+
 	var readProjectIntegration = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
 		tflog.Debug(ctx, "readProjectIntegration")
 		projectIntegration := ProjectIntegration{}
@@ -217,14 +352,38 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 			return diag.FromErr(err)
 		}
 		tflog.Debug(ctx, fmt.Sprintf("projectIntegration Obj: %v", projectIntegration))
-		return packProjectIntegration(ctx, data, projectIntegration)
+
+		// The API always returns the sensitive values redacted, so the only way to detect a change made outside
+		// of Terraform is the update timestamp. Forget the known sensitive values and flag the hashes, so the next
+		// plan sends them again, even when they are only set in the write-only secret_form_json_values.
+		var diags diag.Diagnostics
+		if updatedAt := data.Get("updated_at").(string); updatedAt != "" && updatedAt != projectIntegration.UpdatedAt {
+			tflog.Warn(ctx, fmt.Sprintf("project integration %s was updated outside of Terraform at %s", data.Id(), projectIntegration.UpdatedAt))
+			if errs := RedactSensitiveFormJSONValues(data, "form_json_values"); len(errs) > 0 {
+				return diag.Errorf("failed to pack project integration %q", errs)
+			}
+			if err := data.Set("sensitive_value_hashes", map[string]interface{}{ChangedSensitiveValuesKey: ""}); err != nil {
+				return diag.FromErr(err)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Project integration changed outside of Terraform",
+				Detail:   fmt.Sprintf("Project integration %s was updated outside of Terraform at %s. Its sensitive values can't be compared, the next apply updates the integration with the configured sensitive values.", data.Id(), projectIntegration.UpdatedAt),
+			})
+		}
+
+		if err := data.Set("updated_at", projectIntegration.UpdatedAt); err != nil {
+			return diag.FromErr(err)
+		}
+
+		return append(diags, packProjectIntegration(ctx, data, projectIntegration)...)
 	}
 
 	var createProjectIntegration = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
 		tflog.Debug(ctx, "createProjectIntegration")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		projectIntegration, err := unpackProjectIntegration(data)
+		projectIntegration, err := unpackProjectIntegration(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -240,6 +399,28 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		}
 		data.SetId(strconv.Itoa(result.ID))
 
+		if err := data.Set("updated_at", result.UpdatedAt); err != nil {
+			return diag.FromErr(err)
+		}
+		if diags := packSensitiveValueHashes(data, projectIntegration); diags.HasError() {
+			return diags
+		}
+
+		if data.Get("validate_connection").(bool) {
+			if err := testProjectIntegrationConnection(ctx, m.(*resty.Client), data.Id(), data.Timeout(schema.TimeoutCreate)); err != nil {
+				if data.Get("on_connection_failure").(string) != connectionFailureDelete {
+					return diag.FromErr(err)
+				}
+
+				tflog.Info(ctx, fmt.Sprintf("deleting project integration %s after its connection test failed", data.Id()))
+				if _, deleteErr := m.(*resty.Client).R().Delete(projectIntegrationsUrl + "/" + data.Id()); deleteErr != nil {
+					return diag.Errorf("%s\n\nfailed to delete the project integration %s, it is kept as tainted: %s", err, data.Id(), deleteErr)
+				}
+				data.SetId("")
+				return diag.FromErr(err)
+			}
+		}
+
 		return readProjectIntegration(ctx, data, m)
 	}
 
@@ -247,18 +428,33 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		tflog.Debug(ctx, "updateProjectIntegration")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		projectIntegration, err := unpackProjectIntegration(data)
+		projectIntegration, err := unpackProjectIntegration(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
+		var result ProjectIntegration
 		_, err = m.(*resty.Client).R().
 			SetBody(projectIntegration).
+			SetResult(&result).
 			Put(projectIntegrationsUrl + "/" + data.Id())
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
+		if err := data.Set("updated_at", result.UpdatedAt); err != nil {
+			return diag.FromErr(err)
+		}
+		if diags := packSensitiveValueHashes(data, projectIntegration); diags.HasError() {
+			return diags
+		}
+
+		if data.Get("validate_connection").(bool) {
+			if err := testProjectIntegrationConnection(ctx, m.(*resty.Client), data.Id(), data.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
 		return readProjectIntegration(ctx, data, m)
 	}
 
@@ -277,6 +473,54 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		return nil
 	}
 
+	var customizeDiff = func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		// form_json_values is hashed by label, blocks with the same label would silently collapse into one
+		if err := ValidateUniqueFormJSONLabels(diff.GetRawConfig(), "form_json_values"); err != nil {
+			return err
+		}
+
+		if diff.Id() == "" {
+			return nil
+		}
+
+		sensitiveValues := UnpackSecretFormJSONValues(diff.GetRawConfig(), "secret_form_json_values")
+		for _, keyValue := range diff.Get("form_json_values").(*schema.Set).List() {
+			idx := keyValue.(map[string]interface{})
+			if idx["is_sensitive"].(bool) {
+				sensitiveValues = append(sensitiveValues, FormJSONValues{
+					Label:     idx["label"].(string),
+					Value:     idx["value"].(string),
+					Sensitive: true,
+				})
+			}
+		}
+
+		hashes := diff.Get("sensitive_value_hashes").(map[string]interface{})
+		if _, changed := hashes[ChangedSensitiveValuesKey]; changed {
+			if len(sensitiveValues) == 0 {
+				return nil
+			}
+			tflog.Info(ctx, "project integration was changed outside of Terraform, sending the sensitive values again")
+			return diff.SetNewComputed("sensitive_value_hashes")
+		}
+		// the hashes are unknown after an import. They are seeded by the next update.
+		if len(hashes) == 0 {
+			return nil
+		}
+		if len(hashes) != len(sensitiveValues) {
+			return diff.SetNewComputed("sensitive_value_hashes")
+		}
+		for _, sensitiveValue := range sensitiveValues {
+			hash, ok := hashes[sensitiveValue.Label].(string)
+			if !ok || !SensitiveValueMatches(hash, sensitiveValue.Value) {
+				tflog.Info(ctx, fmt.Sprintf("sensitive value %s does not match the last value sent to the API", sensitiveValue.Label))
+				return diff.SetNewComputed("sensitive_value_hashes")
+			}
+		}
+
+		return nil
+	}
+
 	resourceV1 := &schema.Resource{
 		Schema: projectIntegrationSchemaV1,
 	}
@@ -294,24 +538,52 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 		return rawState, nil
 	}
 
+	resourceV2 := &schema.Resource{
+		Schema: projectIntegrationSchemaV2,
+	}
+
+	var resourceStateUpgradeV2 = func(ctx context.Context, rawState map[string]any, meta any) (map[string]any, error) {
+		// form_json_values changes from a TypeList to a TypeSet. Both are stored as a JSON array in the state,
+		// so the values carry over as-is and are re-hashed by label on the next read. The attributes added in
+		// version 3 are null, and the sensitive value hashes are seeded by the next update, as after an import.
+		return rawState, nil
+	}
+
 	return &schema.Resource{
 		CreateContext: createProjectIntegration,
 		ReadContext:   readProjectIntegration,
 		UpdateContext: updateProjectIntegration,
 		DeleteContext: deleteProjectIntegration,
+		CustomizeDiff: customizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: func(ctx context.Context, data *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				// no hashes yet, so the plans after the import don't compare the sensitive values
+				if err := data.Set("sensitive_value_hashes", map[string]interface{}{}); err != nil {
+					return nil, err
+				}
+				return []*schema.ResourceData{data}, nil
+			},
 		},
 
-		SchemaVersion: 2,
-		Schema:        projectIntegrationSchemaV2,
+		SchemaVersion: 3,
+		Schema:        projectIntegrationSchemaV3,
 		StateUpgraders: []schema.StateUpgrader{
 			{
 				Type:    resourceV1.CoreConfigSchema().ImpliedType(),
 				Upgrade: resourceStateUpgradeV1,
 				Version: 1,
 			},
+			{
+				Type:    resourceV2.CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceStateUpgradeV2,
+				Version: 2,
+			},
 		},
 		Description: "Provides an JFrog Pipelines Project Integration resource.",
 	}
@@ -319,7 +591,7 @@ func PipelineProjectIntegrationResource() *schema.Resource {
 
 func UnpackFormJSONValues(d *util.ResourceData, key string) []FormJSONValues {
 	var formJSONValues []FormJSONValues
-	keyValues := d.Get(key).([]interface{})
+	keyValues := d.Get(key).(*schema.Set).List()
 	for _, keyValue := range keyValues {
 		idx := keyValue.(map[string]interface{})
 		formJSONValue := FormJSONValues{
@@ -332,6 +604,104 @@ func UnpackFormJSONValues(d *util.ResourceData, key string) []FormJSONValues {
 	return formJSONValues
 }
 
+// UnpackSecretFormJSONValues reads the write-only values from the raw configuration. These are only available
+// during apply, the SDK never persists them in ResourceData.
+func UnpackSecretFormJSONValues(config cty.Value, key string) []FormJSONValues {
+	var formJSONValues []FormJSONValues
+	if config.IsNull() || !config.IsKnown() {
+		return formJSONValues
+	}
+
+	secrets := config.GetAttr(key)
+	if secrets.IsNull() || !secrets.IsKnown() {
+		return formJSONValues
+	}
+
+	for it := secrets.ElementIterator(); it.Next(); {
+		_, secret := it.Element()
+		label := secret.GetAttr("label")
+		value := secret.GetAttr("value_wo")
+		if label.IsNull() || !label.IsKnown() || value.IsNull() || !value.IsKnown() {
+			continue
+		}
+		formJSONValues = append(formJSONValues, FormJSONValues{
+			Label:     label.AsString(),
+			Value:     value.AsString(),
+			Sensitive: true,
+		})
+	}
+	return formJSONValues
+}
+
+// ValidateUniqueFormJSONLabels rejects labels set more than once. It checks the raw configuration, as blocks
+// with the same label are already collapsed into one in ResourceDiff.
+func ValidateUniqueFormJSONLabels(config cty.Value, key string) error {
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	values := config.GetAttr(key)
+	if values.IsNull() || !values.IsKnown() {
+		return nil
+	}
+
+	labels := map[string]bool{}
+	for it := values.ElementIterator(); it.Next(); {
+		_, value := it.Element()
+		label := value.GetAttr("label")
+		if label.IsNull() || !label.IsKnown() {
+			continue
+		}
+		if labels[label.AsString()] {
+			return fmt.Errorf("label %q is set more than once in %s", label.AsString(), key)
+		}
+		labels[label.AsString()] = true
+	}
+	return nil
+}
+
+// RedactSensitiveFormJSONValues clears the sensitive values kept in the state, so PackFormJSONValues
+// stores the redacted value returned by the API and the next plan restores the configured value.
+func RedactSensitiveFormJSONValues(d *schema.ResourceData, schemaKey string) []error {
+	setValue := util.MkLens(d)
+	var keyValues []interface{}
+	for _, idx := range UnpackFormJSONValues(&util.ResourceData{ResourceData: d}, schemaKey) {
+		keyValue := map[string]interface{}{
+			"label":        idx.Label,
+			"value":        idx.Value,
+			"is_sensitive": idx.Sensitive,
+		}
+		if idx.Sensitive {
+			keyValue["value"] = ""
+		}
+		keyValues = append(keyValues, keyValue)
+	}
+	return setValue(schemaKey, keyValues)
+}
+
+// HashSensitiveValue returns a salted SHA-256 hash of the value, in the form "salt:hash".
+func HashSensitiveValue(value string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hashWithSalt(hex.EncodeToString(salt), value), nil
+}
+
+// SensitiveValueMatches checks the value against a hash produced by HashSensitiveValue.
+func SensitiveValueMatches(hash string, value string) bool {
+	salt, _, found := strings.Cut(hash, ":")
+	if !found {
+		return false
+	}
+	return hashWithSalt(salt, value) == hash
+}
+
+func hashWithSalt(salt string, value string) string {
+	sum := sha256.Sum256([]byte(salt + value))
+	return salt + ":" + hex.EncodeToString(sum[:])
+}
+
 func PackFormJSONValues(ctx context.Context, d *schema.ResourceData, schemaKey string, formJSONValues []FormJSONValues) []error {
 	setValue := util.MkLens(d)
 	var keyValues []interface{}
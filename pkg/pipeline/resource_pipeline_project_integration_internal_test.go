@@ -0,0 +1,60 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadProjectIntegration_changedOutsideTerraform(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ProjectIntegration{
+			ID:                  42,
+			Name:                "my-integration",
+			MasterIntegrationId: 78,
+			FormJSONValues:      []FormJSONValues{{Label: "url", Value: "http://foo.bar"}, {Label: "token", Value: RedactedFormJSONValue}},
+			UpdatedAt:           "2024-02-01T00:00:00.000Z",
+		})
+	}))
+	t.Cleanup(server.Close)
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	integrationResource := PipelineProjectIntegrationResource()
+	data := integrationResource.TestResourceData()
+	data.SetId("42")
+	for key, value := range map[string]interface{}{
+		"name":                   "my-integration",
+		"master_integration_id":  78,
+		"form_json_values":       []interface{}{map[string]interface{}{"label": "url", "value": "http://foo.bar", "is_sensitive": false}},
+		"sensitive_value_hashes": map[string]interface{}{"token": "salt:hash"},
+		"updated_at":             "2024-01-01T00:00:00.000Z",
+	} {
+		if err := data.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	diags := integrationResource.ReadContext(context.Background(), data, client.SetRetryCount(0))
+	if diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	if len(diags) != 1 || !strings.Contains(diags[0].Summary, "changed outside of Terraform") {
+		t.Errorf("expected a warning about the change, got %v", diags)
+	}
+
+	// the write-only token isn't in form_json_values, so only the flag in the hashes plans the next update
+	hashes := data.Get("sensitive_value_hashes").(map[string]interface{})
+	if _, ok := hashes[ChangedSensitiveValuesKey]; !ok || len(hashes) != 1 {
+		t.Errorf("expected the hashes to be flagged as changed, got %v", hashes)
+	}
+	if updatedAt := data.Get("updated_at").(string); updatedAt != "2024-02-01T00:00:00.000Z" {
+		t.Errorf("expected the new update timestamp, got '%s'", updatedAt)
+	}
+}
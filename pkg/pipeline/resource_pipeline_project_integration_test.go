@@ -2,10 +2,15 @@ package pipeline_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
 	"github.com/jfrog/terraform-provider-pipeline/pkg/pipeline"
@@ -14,7 +19,6 @@ import (
 )
 
 const testFormJsonSchemaKey = "form_json_values"
-const redactedSecretValue = "********"
 
 func TestPackFormJSONValues(t *testing.T) {
 	testCases := map[string]struct {
@@ -48,7 +52,7 @@ func TestPackFormJSONValues(t *testing.T) {
 		"with_asterisks": {
 			input: []pipeline.FormJSONValues{{
 				Label:     "key_a",
-				Value:     redactedSecretValue,
+				Value:     pipeline.RedactedFormJSONValue,
 				Sensitive: true,
 			}, {
 				Label:     "key_b",
@@ -73,7 +77,7 @@ func TestPackFormJSONValues(t *testing.T) {
 		"with_asterisks_no_state": {
 			input: []pipeline.FormJSONValues{{
 				Label:     "key_a",
-				Value:     redactedSecretValue,
+				Value:     pipeline.RedactedFormJSONValue,
 				Sensitive: false,
 			}, {
 				Label:     "key_b",
@@ -82,7 +86,7 @@ func TestPackFormJSONValues(t *testing.T) {
 			},
 			},
 			existingState: []pipeline.FormJSONValues{},
-			result:        map[string]string{"key_a": redactedSecretValue, "key_b": "something_else"},
+			result:        map[string]string{"key_a": pipeline.RedactedFormJSONValue, "key_b": "something_else"},
 		},
 	}
 
@@ -119,6 +123,318 @@ func TestPackFormJSONValues(t *testing.T) {
 	}
 }
 
+func TestUnpackSecretFormJSONValues(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"secret_form_json_values": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"label":    cty.StringVal("token"),
+				"value_wo": cty.StringVal("super_secret"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"label":    cty.StringVal("password"),
+				"value_wo": cty.NullVal(cty.String),
+			}),
+		}),
+	})
+
+	secrets := pipeline.UnpackSecretFormJSONValues(config, "secret_form_json_values")
+	if len(secrets) != 1 {
+		t.Fatalf("expected 1 secret value, got %d", len(secrets))
+	}
+	if secrets[0].Label != "token" || secrets[0].Value != "super_secret" || !secrets[0].Sensitive {
+		t.Errorf("unexpected secret value %+v", secrets[0])
+	}
+
+	if secrets := pipeline.UnpackSecretFormJSONValues(cty.NullVal(config.Type()), "secret_form_json_values"); len(secrets) != 0 {
+		t.Errorf("expected no secret values from null config, got %d", len(secrets))
+	}
+}
+
+func TestValidateUniqueFormJSONLabels(t *testing.T) {
+	formJSONValue := func(label, value string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"label": cty.StringVal(label),
+			"value": cty.StringVal(value),
+		})
+	}
+
+	config := cty.ObjectVal(map[string]cty.Value{
+		"form_json_values": cty.SetVal([]cty.Value{
+			formJSONValue("url", "http://foo.bar"),
+			formJSONValue("token", "super_secret"),
+		}),
+	})
+	if err := pipeline.ValidateUniqueFormJSONLabels(config, testFormJsonSchemaKey); err != nil {
+		t.Errorf("expected unique labels to be valid, got %v", err)
+	}
+
+	config = cty.ObjectVal(map[string]cty.Value{
+		"form_json_values": cty.SetVal([]cty.Value{
+			formJSONValue("url", "http://foo.bar"),
+			formJSONValue("url", "http://bar.baz"),
+		}),
+	})
+	if err := pipeline.ValidateUniqueFormJSONLabels(config, testFormJsonSchemaKey); err == nil {
+		t.Error("expected an error for a duplicate label")
+	}
+}
+
+func TestProjectIntegrationStateUpgradeV2(t *testing.T) {
+	// state written by the last release with schema version 2, where form_json_values is a list
+	v2State := []byte(`{
+		"id": "42",
+		"name": "my-integration",
+		"project_id": 1,
+		"project": [],
+		"master_integration_id": 78,
+		"master_integration_name": "slackKey",
+		"form_json_values": [
+			{"label": "url", "value": "http://foo.bar", "is_sensitive": false},
+			{"label": "token", "value": "super_secret", "is_sensitive": true}
+		],
+		"environments": ["DEV"],
+		"is_internal": false
+	}`)
+
+	integrationResource := pipeline.PipelineProjectIntegrationResource()
+	var upgrader *schema.StateUpgrader
+	for i := range integrationResource.StateUpgraders {
+		if integrationResource.StateUpgraders[i].Version == 2 {
+			upgrader = &integrationResource.StateUpgraders[i]
+		}
+	}
+	if upgrader == nil {
+		t.Fatal("no state upgrader from version 2")
+	}
+
+	// the schema of version 2 must stay as released, so its states still decode
+	if _, err := ctyjson.Unmarshal(v2State, upgrader.Type); err != nil {
+		t.Fatalf("state of version 2 does not match the schema of the upgrader: %v", err)
+	}
+	for _, attribute := range []string{"project_key", "secret_form_json_values", "secret_version", "sensitive_value_hashes", "updated_at", "validate_connection", "on_connection_failure"} {
+		if upgrader.Type.HasAttribute(attribute) {
+			t.Errorf("expected %s to be added in version 3, not in version 2", attribute)
+		}
+	}
+	if !upgrader.Type.AttributeType("form_json_values").IsListType() {
+		t.Errorf("expected form_json_values to be a list in version 2, got %s", upgrader.Type.AttributeType("form_json_values").FriendlyName())
+	}
+
+	rawState := map[string]interface{}{}
+	if err := json.Unmarshal(v2State, &rawState); err != nil {
+		t.Fatalf("error decoding the state: %v", err)
+	}
+	upgraded, err := upgrader.Upgrade(context.TODO(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error upgrading the state: %v", err)
+	}
+
+	// decode the upgraded state with the current schema, as Terraform does after upgrading
+	upgradedJson, err := json.Marshal(upgraded)
+	if err != nil {
+		t.Fatalf("error encoding the upgraded state: %v", err)
+	}
+	value, err := ctyjson.Unmarshal(upgradedJson, integrationResource.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("upgraded state does not match the current schema: %v", err)
+	}
+	formJSONValues := value.GetAttr("form_json_values")
+	if !formJSONValues.Type().IsSetType() || formJSONValues.LengthInt() != 2 {
+		t.Fatalf("expected form_json_values to be converted to a set of 2 values, got %s", formJSONValues.GoString())
+	}
+	state, err := integrationResource.ShimInstanceStateFromValue(value)
+	if err != nil {
+		t.Fatalf("error shimming the upgraded state: %v", err)
+	}
+
+	// the values are keyed by the hash of their label instead of their position in the list
+	for _, label := range []string{"url", "token"} {
+		key := fmt.Sprintf("form_json_values.%d.label", schema.HashString(label))
+		if state.Attributes[key] != label {
+			t.Errorf("expected %s to be '%s', got '%s'", key, label, state.Attributes[key])
+		}
+	}
+
+	data := integrationResource.Data(state)
+	expected := map[string]pipeline.FormJSONValues{
+		"url":   {Label: "url", Value: "http://foo.bar"},
+		"token": {Label: "token", Value: "super_secret", Sensitive: true},
+	}
+	values := pipeline.UnpackFormJSONValues(&util.ResourceData{ResourceData: data}, testFormJsonSchemaKey)
+	if len(values) != len(expected) {
+		t.Fatalf("expected %d form_json_values, got %d", len(expected), len(values))
+	}
+	for _, value := range values {
+		if expected[value.Label] != value {
+			t.Errorf("label %s upgraded to %+v; expected %+v", value.Label, value, expected[value.Label])
+		}
+	}
+	if hashes := data.Get("sensitive_value_hashes").(map[string]interface{}); len(hashes) != 0 {
+		t.Errorf("expected no sensitive_value_hashes before the next update, got %v", hashes)
+	}
+}
+
+func TestProjectIntegrationCustomizeDiff_importedHashes(t *testing.T) {
+	hash := strconv.Itoa(schema.HashString("token"))
+	// state of an imported integration, after a first update restored the sensitive value but before the hashes are set
+	state := &terraform.InstanceState{
+		ID: "42",
+		Attributes: map[string]string{
+			"id":                                  "42",
+			"name":                                "my-integration",
+			"project_id":                          "1",
+			"master_integration_id":               "78",
+			"form_json_values.#":                  "1",
+			"form_json_values." + hash + ".label": "token",
+			"form_json_values." + hash + ".value": "super_secret",
+			"form_json_values." + hash + ".is_sensitive": "true",
+			"sensitive_value_hashes.%":                   "0",
+			"validate_connection":                        "false",
+			"on_connection_failure":                      "taint",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                  "my-integration",
+		"project_id":            1,
+		"master_integration_id": 78,
+		"form_json_values": []interface{}{
+			map[string]interface{}{"label": "token", "value": "super_secret", "is_sensitive": true},
+		},
+	})
+
+	diff, err := pipeline.PipelineProjectIntegrationResource().Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		for key, attribute := range diff.Attributes {
+			t.Errorf("expected no diff without hashes in the state, got %s: %+v", key, *attribute)
+		}
+	}
+}
+
+func TestProjectIntegrationCustomizeDiff_changedSecretFormJSONValues(t *testing.T) {
+	integrationResource := pipeline.PipelineProjectIntegrationResource()
+	hash := strconv.Itoa(schema.HashString("url"))
+	// the only sensitive value is write-only, and the integration was changed outside of Terraform
+	state := &terraform.InstanceState{
+		ID: "42",
+		Attributes: map[string]string{
+			"id":                                  "42",
+			"name":                                "my-integration",
+			"project_id":                          "1",
+			"master_integration_id":               "78",
+			"form_json_values.#":                  "1",
+			"form_json_values." + hash + ".label": "url",
+			"form_json_values." + hash + ".value": "http://foo.bar",
+			"form_json_values." + hash + ".is_sensitive":                   "false",
+			"secret_form_json_values.#":                                    "0",
+			"sensitive_value_hashes.%":                                     "1",
+			"sensitive_value_hashes." + pipeline.ChangedSensitiveValuesKey: "",
+			"validate_connection":                                          "false",
+			"on_connection_failure":                                        "taint",
+		},
+	}
+	rawConfig, err := ctyjson.Unmarshal([]byte(`{
+		"name": "my-integration",
+		"project_id": 1,
+		"master_integration_id": 78,
+		"form_json_values": [{"label": "url", "value": "http://foo.bar", "is_sensitive": false}],
+		"secret_form_json_values": [{"label": "token", "value_wo": "super_secret"}]
+	}`), integrationResource.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding the config: %v", err)
+	}
+	state.RawConfig = rawConfig
+	config := terraform.NewResourceConfigShimmed(rawConfig, integrationResource.CoreConfigSchema())
+
+	diff, err := integrationResource.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil || diff.Attributes["sensitive_value_hashes.%"] == nil || !diff.Attributes["sensitive_value_hashes.%"].NewComputed {
+		t.Errorf("expected an update sending the write-only values again, got %+v", diff)
+	}
+
+	// without sensitive values, there is nothing to send again
+	rawConfig, err = ctyjson.Unmarshal([]byte(`{
+		"name": "my-integration",
+		"project_id": 1,
+		"master_integration_id": 78,
+		"form_json_values": [{"label": "url", "value": "http://foo.bar", "is_sensitive": false}]
+	}`), integrationResource.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding the config: %v", err)
+	}
+	state.RawConfig = rawConfig
+	config = terraform.NewResourceConfigShimmed(rawConfig, integrationResource.CoreConfigSchema())
+
+	diff, err = integrationResource.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		for key, attribute := range diff.Attributes {
+			t.Errorf("expected no diff without sensitive values, got %s: %+v", key, *attribute)
+		}
+	}
+}
+
+func TestSensitiveValueHashes(t *testing.T) {
+	hash, err := pipeline.HashSensitiveValue("super_secret")
+	if err != nil {
+		t.Fatalf("error hashing value: %v", err)
+	}
+
+	if !pipeline.SensitiveValueMatches(hash, "super_secret") {
+		t.Errorf("hash %s does not match its value", hash)
+	}
+	if pipeline.SensitiveValueMatches(hash, "rotated_secret") {
+		t.Errorf("hash %s matches a different value", hash)
+	}
+	if pipeline.SensitiveValueMatches("not_a_hash", "super_secret") {
+		t.Errorf("malformed hash matches value")
+	}
+
+	otherHash, err := pipeline.HashSensitiveValue("super_secret")
+	if err != nil {
+		t.Fatalf("error hashing value: %v", err)
+	}
+	if hash == otherHash {
+		t.Errorf("expected different salts, got identical hashes %s", hash)
+	}
+}
+
+func TestRedactSensitiveFormJSONValues(t *testing.T) {
+	schemaData := pipeline.PipelineProjectIntegrationResource().TestResourceData()
+	err := schemaData.Set(testFormJsonSchemaKey, []interface{}{
+		map[string]interface{}{"label": "key_a", "value": "super_secret", "is_sensitive": true},
+		map[string]interface{}{"label": "key_b", "value": "something_else", "is_sensitive": false},
+	})
+	if err != nil {
+		t.Fatalf("error creating test schema %v", err)
+	}
+
+	if errs := pipeline.RedactSensitiveFormJSONValues(schemaData, testFormJsonSchemaKey); len(errs) > 0 {
+		t.Fatalf("error bubbled from RedactSensitiveFormJSONValues: %v", errs)
+	}
+
+	errs := pipeline.PackFormJSONValues(context.TODO(), schemaData, testFormJsonSchemaKey, []pipeline.FormJSONValues{
+		{Label: "key_a", Value: pipeline.RedactedFormJSONValue},
+		{Label: "key_b", Value: "something_else"},
+	})
+	for _, err := range errs {
+		t.Errorf("error bubbled from packFormJSONValues: %v", err)
+	}
+
+	expected := map[string]string{"key_a": pipeline.RedactedFormJSONValue, "key_b": "something_else"}
+	for _, value := range pipeline.UnpackFormJSONValues(&util.ResourceData{ResourceData: schemaData}, testFormJsonSchemaKey) {
+		if expected[value.Label] != value.Value {
+			t.Errorf("key %s returned %s; expected %s", value.Label, value.Value, expected[value.Label])
+		}
+	}
+}
+
 func TestAccProjectIntegration_withProject(t *testing.T) {
 	projectKey := fmt.Sprintf("t%d", test.RandomInt())
 	_, fqrn, name := test.MkNames(projectKey, "pipeline_project_integration")
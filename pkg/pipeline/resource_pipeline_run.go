@@ -0,0 +1,291 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+type RunTrigger struct {
+	EnvironmentVariables map[string]string `json:"environmentVariables,omitempty"`
+}
+
+func getRun(client *resty.Client, id int) (*Run, error) {
+	var runs []Run
+	_, err := client.R().
+		SetQueryParam("runIds", strconv.Itoa(id)).
+		SetResult(&runs).
+		Get(runsUrl)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0], nil
+}
+
+// waitForRun polls the run until it completes, and fails if it didn't succeed
+func waitForRun(ctx context.Context, client *resty.Client, id int, timeout time.Duration) error {
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		run, err := getRun(client, id)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		if run == nil {
+			return retry.NonRetryableError(fmt.Errorf("run %d not found", id))
+		}
+		tflog.Debug(ctx, fmt.Sprintf("run %d is %s", id, statusName(run.StatusCode)))
+
+		if !isTerminalStatus(run.StatusCode) {
+			return retry.RetryableError(fmt.Errorf("run %d is %s", id, statusName(run.StatusCode)))
+		}
+		if run.StatusCode != statusSuccess {
+			return retry.NonRetryableError(fmt.Errorf("run %d of pipeline %d completed with status %s", run.RunNumber, run.PipelineId, statusName(run.StatusCode)))
+		}
+		return nil
+	})
+}
+
+// findEntryStep returns the step of the pipeline that doesn't take other steps as input
+func findEntryStep(steps []PipelineStep) (PipelineStep, error) {
+	var entrySteps []PipelineStep
+	var names []string
+	for _, step := range steps {
+		if len(step.ConfigPropertyBag.InputSteps) == 0 {
+			entrySteps = append(entrySteps, step)
+			names = append(names, step.Name)
+		}
+	}
+
+	if len(entrySteps) != 1 {
+		return PipelineStep{}, fmt.Errorf("expected the pipeline to have one step without input steps, found %d %v, set step_name to choose the step to trigger", len(entrySteps), names)
+	}
+	return entrySteps[0], nil
+}
+
+func pipelineRunResource() *schema.Resource {
+
+	var runSchema = map[string]*schema.Schema{
+		"pipeline_name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "The name of the pipeline to run.",
+		},
+		"branch": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "The branch of the pipeline, for multi-branch pipeline sources.",
+		},
+		"pipeline_source_id": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Id of the pipeline source of the pipeline. Narrows down the lookup of the pipeline.",
+		},
+		"step_name": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "The name of the step to trigger. Default to the step of the pipeline that has no other steps as input. Required when the pipeline has several such steps.",
+		},
+		"environment_variables": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Description: "Environment variables of the run, overriding the ones of the pipeline.",
+		},
+		"triggers": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Description: "Arbitrary map of values that, when changed, triggers a new run. The pipeline is only run once otherwise.",
+		},
+		"wait_for_completion": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Wait until the run completes, up to the create timeout, and fail if it didn't succeed. Default to `false`.",
+		},
+		"pipeline_id": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Id of the pipeline.",
+		},
+		"run_number": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Number of the run in the pipeline.",
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Status of the run, e.g. `processing`, `success` or `failure`.",
+		},
+		"duration_seconds": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "Duration of the run, in seconds.",
+		},
+	}
+
+	var packRun = func(d *schema.ResourceData, run Run) diag.Diagnostics {
+		setValue := util.MkLens(d)
+
+		errs := setValue("pipeline_id", run.PipelineId)
+		errs = append(errs, setValue("run_number", run.RunNumber)...)
+		errs = append(errs, setValue("status", statusName(run.StatusCode))...)
+		errs = append(errs, setValue("duration_seconds", run.DurationSeconds)...)
+		if len(errs) > 0 {
+			return diag.Errorf("failed to pack run %q", errs)
+		}
+
+		return nil
+	}
+
+	var readRun = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "readRun")
+
+		id, err := strconv.Atoi(data.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		run, err := getRun(m.(*resty.Client), id)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if run == nil {
+			tflog.Warn(ctx, fmt.Sprintf("run %d not found, removing from state", id))
+			data.SetId("")
+			return nil
+		}
+
+		return packRun(data, *run)
+	}
+
+	var createRun = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "createRun")
+		d := &util.ResourceData{ResourceData: data}
+		client := m.(*resty.Client)
+
+		pipeline, err := findPipeline(client, d.GetString("pipeline_name", false), d.GetInt("pipeline_source_id", false), d.GetString("branch", false))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var steps []PipelineStep
+		_, err = client.R().
+			SetQueryParam("pipelineIds", strconv.Itoa(pipeline.ID)).
+			SetResult(&steps).
+			Get(pipelineStepsUrl)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if len(steps) == 0 {
+			return diag.Errorf("pipeline %s has no steps", pipeline.Name)
+		}
+
+		var step PipelineStep
+		if stepName := d.GetString("step_name", false); stepName != "" {
+			found := false
+			for _, s := range steps {
+				if s.Name == stepName {
+					step, found = s, true
+					break
+				}
+			}
+			if !found {
+				return diag.Errorf("no step found with name '%s' in pipeline %s", stepName, pipeline.Name)
+			}
+		} else {
+			step, err = findEntryStep(steps)
+			if err != nil {
+				return diag.Errorf("failed to find the step to trigger in pipeline %s: %s", pipeline.Name, err)
+			}
+		}
+
+		trigger := RunTrigger{
+			EnvironmentVariables: map[string]string{},
+		}
+		for key, value := range data.Get("environment_variables").(map[string]interface{}) {
+			trigger.EnvironmentVariables[key] = value.(string)
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("triggering step %s of pipeline %s", step.Name, pipeline.Name))
+		resp, err := client.R().
+			SetBody(trigger).
+			Post(pipelineStepsUrl + "/" + strconv.Itoa(step.ID) + "/trigger")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var run Run
+		if err := json.Unmarshal(resp.Body(), &run); err != nil {
+			return diag.FromErr(err)
+		}
+		// without an Id the triggered run can't be tracked, and would be silently removed from the state
+		if run.ID == 0 {
+			return diag.Errorf("step %s of pipeline %s was triggered, but the response has no run Id: %s", step.Name, pipeline.Name, resp.String())
+		}
+		data.SetId(strconv.Itoa(run.ID))
+
+		if data.Get("wait_for_completion").(bool) {
+			if err := waitForRun(ctx, client, run.ID, data.Timeout(schema.TimeoutCreate)); err != nil {
+				// keep the failed run in the state, it is tainted and the next apply triggers a new run
+				if diags := readRun(ctx, data, m); diags.HasError() {
+					return diags
+				}
+				return diag.FromErr(err)
+			}
+		}
+
+		return readRun(ctx, data, m)
+	}
+
+	var updateRun = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "updateRun")
+		return readRun(ctx, data, m)
+	}
+
+	var deleteRun = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, fmt.Sprintf("deleteRun: run %s is kept in the history of the pipeline", data.Id()))
+		return nil
+	}
+
+	return &schema.Resource{
+		CreateContext: createRun,
+		ReadContext:   readRun,
+		UpdateContext: updateRun,
+		DeleteContext: deleteRun,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema:      runSchema,
+		Description: "Triggers a run of a pipeline, e.g. to bootstrap an environment created by Terraform. The pipeline runs once, and again when `triggers` change. Destroying the resource keeps the run in the history of the pipeline.",
+	}
+}
@@ -0,0 +1,115 @@
+package pipeline_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jfrog/terraform-provider-pipeline/pkg/acctest"
+	"github.com/jfrog/terraform-provider-shared/test"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+// getTestPipelineName returns the name of a pipeline already synced in the test instance, pipelines
+// can't be created without a pipeline source backed by a git repository
+func getTestPipelineName(t *testing.T, envVar string) string {
+	name := os.Getenv(envVar)
+	if name == "" {
+		t.Skipf("%s must be set to the name of a synced pipeline", envVar)
+	}
+	return name
+}
+
+func TestAccRun(t *testing.T) {
+	pipelineName := getTestPipelineName(t, "PIPELINES_TEST_PIPELINE")
+	_, fqrn, name := test.MkNames("run", "pipeline_run")
+
+	const template = `
+		resource "pipeline_run" "{{ .name }}" {
+			pipeline_name       = "{{ .pipelineName }}"
+			wait_for_completion = true
+
+			environment_variables = {
+				TF_ACC_RUN = "{{ .trigger }}"
+			}
+
+			triggers = {
+				trigger = "{{ .trigger }}"
+			}
+		}
+	`
+	params := map[string]interface{}{
+		"name":         name,
+		"pipelineName": pipelineName,
+		"trigger":      "1",
+	}
+	config := util.ExecuteTemplate("TestAccRun", template, params)
+	params["trigger"] = "2"
+	retriggeredConfig := util.ExecuteTemplate("TestAccRunRetriggered", template, params)
+
+	var firstRunId string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { acctest.PreCheck(t) },
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "status", "success"),
+					resource.TestCheckResourceAttrSet(fqrn, "pipeline_id"),
+					resource.TestCheckResourceAttrSet(fqrn, "run_number"),
+					func(s *terraform.State) error {
+						firstRunId = s.RootModule().Resources[fqrn].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				Config: retriggeredConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fqrn, "status", "success"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources[fqrn].Primary.ID; id == firstRunId {
+							return fmt.Errorf("expected a new run when triggers change, got run %s again", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccRun_failure(t *testing.T) {
+	pipelineName := getTestPipelineName(t, "PIPELINES_TEST_FAILING_PIPELINE")
+	_, _, name := test.MkNames("run", "pipeline_run")
+
+	config := util.ExecuteTemplate("TestAccRunFailure", `
+		resource "pipeline_run" "{{ .name }}" {
+			pipeline_name       = "{{ .pipelineName }}"
+			wait_for_completion = true
+		}
+	`, map[string]interface{}{
+		"name":         name,
+		"pipelineName": pipelineName,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { acctest.PreCheck(t) },
+		ProviderFactories: acctest.ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`completed with status (failure|error)`),
+			},
+		},
+	})
+}
@@ -50,9 +50,17 @@ func pipelineSourceResource() *schema.Resource {
 		},
 		"project_id": {
 			Type:         schema.TypeInt,
-			Required:     true,
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"project_id", "project_key"},
 			ValidateFunc: validation.IntAtLeast(0),
-			Description:  "Id of the project where the pipeline source will live.",
+			Description:  "Id of the project where the pipeline source will live. Exactly one of `project_id` and `project_key` must be set.",
+		},
+		"project_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Key of the project where the pipeline source will live. Exactly one of `project_id` and `project_key` must be set.",
 		},
 		"project_integration_id": {
 			Type:         schema.TypeInt,
@@ -111,11 +119,16 @@ func pipelineSourceResource() *schema.Resource {
 		},
 	}
 
-	var unpackPipelineSource = func(data *schema.ResourceData) (PipelineSource, error) {
+	var unpackPipelineSource = func(data *schema.ResourceData, m interface{}) (PipelineSource, error) {
 		d := &util.ResourceData{ResourceData: data}
 
+		projectId, err := resolveProjectId(d, m)
+		if err != nil {
+			return PipelineSource{}, err
+		}
+
 		pipelineSource := PipelineSource{
-			ProjectId:            d.GetInt("project_id", false),
+			ProjectId:            projectId,
 			Name:                 d.GetString("name", false),
 			ProjectIntegrationId: d.GetInt("project_integration_id", false),
 			RepositoryFullName:   d.GetString("repository_full_name", false),
@@ -168,7 +181,7 @@ func pipelineSourceResource() *schema.Resource {
 		tflog.Debug(ctx, "createPipelineSource")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		pipelineSource, err := unpackPipelineSource(data)
+		pipelineSource, err := unpackPipelineSource(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -192,7 +205,7 @@ func pipelineSourceResource() *schema.Resource {
 		tflog.Debug(ctx, "updatePipelineSource")
 		tflog.Trace(ctx, fmt.Sprintf("%+v\n", data))
 
-		pipelineSource, err := unpackPipelineSource(data)
+		pipelineSource, err := unpackPipelineSource(data, m)
 		if err != nil {
 			return diag.FromErr(err)
 		}
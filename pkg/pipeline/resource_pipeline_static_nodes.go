@@ -0,0 +1,426 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/jfrog/terraform-provider-shared/util"
+)
+
+// staticNodesDefaultConcurrency is the concurrency of the API calls when it isn't configured, or after an import
+const staticNodesDefaultConcurrency = 5
+
+// runConcurrently runs the tasks with at most `concurrency` of them at the same time, and returns all their errors
+func runConcurrently(concurrency int, tasks []func() error) error {
+	// an unbuffered semaphore would block the first task
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	errs := make([]error, len(tasks))
+
+	for i, task := range tasks {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, task func() error) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[i] = task()
+		}(i, task)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// validateUniqueStaticNodeNames rejects friendly names set more than once. It checks the raw configuration, as
+// ResourceDiff keys the node blocks by their set hash, not by friendly name.
+func validateUniqueStaticNodeNames(config cty.Value) error {
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	nodes := config.GetAttr("node")
+	if nodes.IsNull() || !nodes.IsKnown() {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for it := nodes.ElementIterator(); it.Next(); {
+		_, node := it.Element()
+		name := node.GetAttr("friendly_name")
+		if name.IsNull() || !name.IsKnown() {
+			continue
+		}
+		if names[name.AsString()] {
+			return fmt.Errorf("friendly_name %q is set in more than one node block", name.AsString())
+		}
+		names[name.AsString()] = true
+	}
+	return nil
+}
+
+func pipelineStaticNodesResource() *schema.Resource {
+
+	var staticNodesSchema = map[string]*schema.Schema{
+		"node_pool_id": {
+			Type:         schema.TypeInt,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.IntAtLeast(1),
+			Description:  "Id of the static node pool of the nodes.",
+		},
+		"project_id": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ForceNew:     true,
+			ExactlyOneOf: []string{"project_id", "project_key"},
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Id of the project of the nodes. Exactly one of `project_id` and `project_key` must be set.",
+		},
+		"project_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Key of the project of the nodes. Exactly one of `project_id` and `project_key` must be set.",
+		},
+		"node": {
+			Type:     schema.TypeSet,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"friendly_name": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringIsNotEmpty,
+						Description:  "The name of the node. Should be prefixed with the project key.",
+					},
+					"ip_address": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringIsNotEmpty,
+						Description:  "Node address for auto-initialization.",
+					},
+					"is_auto_initialized": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Determine auto or manual initialization. Default to `true`.",
+					},
+					"is_swap_enabled": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Enable/disable the use of swap space. Not available to Windows node pools. Default to `false`.",
+					},
+				},
+			},
+			Description: "A node of the node pool, identified by its friendly name, which must be unique. Nodes are added and removed individually when the blocks change, the other nodes of the node pool are left untouched.",
+		},
+		"concurrency": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      staticNodesDefaultConcurrency,
+			ValidateFunc: validation.IntBetween(1, 50),
+			Description:  fmt.Sprintf("Number of nodes registered, updated or removed at the same time. Default to `%d`.", staticNodesDefaultConcurrency),
+		},
+		"node_ids": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+			Description: "Ids of the nodes, keyed by friendly name.",
+		},
+		"tokens": {
+			Type:      schema.TypeMap,
+			Computed:  true,
+			Sensitive: true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Description: "Tokens of the nodes, keyed by friendly name.",
+		},
+	}
+
+	var unpackStaticNodes = func(data *schema.ResourceData, m interface{}) (map[string]Node, error) {
+		d := &util.ResourceData{ResourceData: data}
+
+		projectId, err := resolveProjectId(d, m)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes := map[string]Node{}
+		for _, raw := range data.Get("node").(*schema.Set).List() {
+			values := raw.(map[string]interface{})
+			node := Node{
+				FriendlyName:      values["friendly_name"].(string),
+				ProjectId:         projectId,
+				NodePoolId:        d.GetInt("node_pool_id", false),
+				IsOnDemand:        false,
+				IsAutoInitialized: values["is_auto_initialized"].(bool),
+				IPAddress:         values["ip_address"].(string),
+				IsSwapEnabled:     values["is_swap_enabled"].(bool),
+			}
+			nodes[node.FriendlyName] = node
+		}
+		return nodes, nil
+	}
+
+	var packStaticNodes = func(d *schema.ResourceData, nodes []Node) diag.Diagnostics {
+		var packedNodes []interface{}
+		nodeIds := map[string]interface{}{}
+		tokens := map[string]interface{}{}
+		for _, node := range nodes {
+			packedNodes = append(packedNodes, map[string]interface{}{
+				"friendly_name":       node.FriendlyName,
+				"ip_address":          node.IPAddress,
+				"is_auto_initialized": node.IsAutoInitialized,
+				"is_swap_enabled":     node.IsSwapEnabled,
+			})
+			nodeIds[node.FriendlyName] = node.ID
+			tokens[node.FriendlyName] = node.SystemPropertyBag.Token
+		}
+
+		setValue := util.MkLens(d)
+		errs := setValue("node", packedNodes)
+		errs = append(errs, setValue("node_ids", nodeIds)...)
+		errs = append(errs, setValue("tokens", tokens)...)
+		if len(errs) > 0 {
+			return diag.Errorf("failed to pack static nodes %q", errs)
+		}
+
+		return nil
+	}
+
+	var readStaticNodes = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "readStaticNodes")
+
+		nodePoolId, err := strconv.Atoi(data.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		nodes, err := getNodePoolNodes(m.(*resty.Client), nodePoolId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// only the nodes created by this resource, or found when importing it, are managed
+		managed := data.Get("node_ids").(map[string]interface{})
+		var managedNodes []Node
+		projectId := 0
+		for _, node := range nodes {
+			if id, ok := managed[node.FriendlyName]; ok && id.(int) == node.ID {
+				managedNodes = append(managedNodes, node)
+				projectId = node.ProjectId
+			}
+		}
+		tflog.Debug(ctx, fmt.Sprintf("found %d of %d managed nodes in node pool %d", len(managedNodes), len(managed), nodePoolId))
+
+		errs := util.MkLens(data)("node_pool_id", nodePoolId)
+		if projectId != 0 {
+			errs = append(errs, util.MkLens(data)("project_id", projectId)...)
+		}
+		if len(errs) > 0 {
+			return diag.Errorf("failed to pack static nodes %q", errs)
+		}
+
+		return packStaticNodes(data, managedNodes)
+	}
+
+	var createStaticNode = func(client *resty.Client, node Node) (int, error) {
+		resp, err := client.R().SetBody(node).Post(nodesUrl)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create node %s: %w", node.FriendlyName, err)
+		}
+
+		var result Node
+		if err := json.Unmarshal(resp.Body(), &result); err != nil {
+			return 0, err
+		}
+		return result.ID, nil
+	}
+
+	var deleteStaticNode = func(client *resty.Client, name string, id int) error {
+		resp, err := client.R().Delete(nodesUrl + "/" + strconv.Itoa(id))
+		if err != nil && resp.StatusCode() != http.StatusNotFound {
+			return fmt.Errorf("failed to delete node %s: %w", name, err)
+		}
+		return nil
+	}
+
+	// applyStaticNodes creates, updates and deletes the nodes concurrently, and keeps the ids of the nodes that succeeded.
+	// The state is read back even when some nodes failed, so the next plan only retries the failed nodes. The errors
+	// of the nodes are returned apart from the diagnostics, for the caller to report.
+	var applyStaticNodes = func(ctx context.Context, data *schema.ResourceData, m interface{}) (diag.Diagnostics, error) {
+		client := m.(*resty.Client)
+
+		nodes, err := unpackStaticNodes(data, m)
+		if err != nil {
+			return diag.FromErr(err), nil
+		}
+
+		var lock sync.Mutex
+		nodeIds := data.Get("node_ids").(map[string]interface{})
+
+		// the nodes whose settings changed, the set hash covers all the settings of a node
+		changed := map[string]bool{}
+		if data.HasChange("node") {
+			old, new := data.GetChange("node")
+			for _, raw := range new.(*schema.Set).Difference(old.(*schema.Set)).List() {
+				changed[raw.(map[string]interface{})["friendly_name"].(string)] = true
+			}
+		}
+
+		var tasks []func() error
+		for name, id := range nodeIds {
+			if _, ok := nodes[name]; !ok {
+				name, id := name, id.(int)
+				tasks = append(tasks, func() error {
+					if err := deleteStaticNode(client, name, id); err != nil {
+						return err
+					}
+					lock.Lock()
+					defer lock.Unlock()
+					delete(nodeIds, name)
+					return nil
+				})
+			}
+		}
+		for name, node := range nodes {
+			name, node := name, node
+			id, exists := nodeIds[name]
+			switch {
+			case !exists:
+				tasks = append(tasks, func() error {
+					id, err := createStaticNode(client, node)
+					if err != nil {
+						return err
+					}
+					lock.Lock()
+					defer lock.Unlock()
+					nodeIds[name] = id
+					return nil
+				})
+			case changed[name]:
+				tasks = append(tasks, func() error {
+					_, err := client.R().SetBody(node).Put(nodesUrl + "/" + strconv.Itoa(id.(int)))
+					if err != nil {
+						return fmt.Errorf("failed to update node %s: %w", name, err)
+					}
+					return nil
+				})
+			}
+		}
+		tflog.Debug(ctx, fmt.Sprintf("applying %d node changes", len(tasks)))
+
+		applyErr := runConcurrently(data.Get("concurrency").(int), tasks)
+
+		if data.Id() == "" {
+			data.SetId(strconv.Itoa(data.Get("node_pool_id").(int)))
+		}
+		if err := data.Set("node_ids", nodeIds); err != nil {
+			return diag.FromErr(err), applyErr
+		}
+
+		return readStaticNodes(ctx, data, m), applyErr
+	}
+
+	var createStaticNodes = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "createStaticNodes")
+
+		diags, err := applyStaticNodes(ctx, data, m)
+		if err == nil || diags.HasError() {
+			return append(diags, diag.FromErr(err)...)
+		}
+		if len(data.Get("node_ids").(map[string]interface{})) == 0 {
+			data.SetId("")
+			return diag.FromErr(err)
+		}
+
+		// the created nodes are kept in the state, but Terraform taints the resource after a failed create
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Some nodes were not created",
+			Detail:   fmt.Sprintf("%s\n\nThe nodes that were created are kept in the state. Untaint the resource so the next apply only creates the missing nodes, otherwise it replaces all of them.", err),
+		})
+	}
+
+	var updateStaticNodes = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "updateStaticNodes")
+
+		diags, err := applyStaticNodes(ctx, data, m)
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	var deleteStaticNodes = func(ctx context.Context, data *schema.ResourceData, m interface{}) diag.Diagnostics {
+		tflog.Debug(ctx, "deleteStaticNodes")
+		client := m.(*resty.Client)
+
+		var tasks []func() error
+		for name, id := range data.Get("node_ids").(map[string]interface{}) {
+			name, id := name, id.(int)
+			tasks = append(tasks, func() error {
+				return deleteStaticNode(client, name, id)
+			})
+		}
+
+		return diag.FromErr(runConcurrently(data.Get("concurrency").(int), tasks))
+	}
+
+	return &schema.Resource{
+		CreateContext: createStaticNodes,
+		ReadContext:   readStaticNodes,
+		UpdateContext: updateStaticNodes,
+		DeleteContext: deleteStaticNodes,
+
+		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+			return validateUniqueStaticNodeNames(diff.GetRawConfig())
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, data *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+				nodePoolId, err := strconv.Atoi(data.Id())
+				if err != nil {
+					return nil, fmt.Errorf("expected a node pool id, got '%s'", data.Id())
+				}
+
+				nodes, err := getNodePoolNodes(m.(*resty.Client), nodePoolId)
+				if err != nil {
+					return nil, err
+				}
+				nodeIds := map[string]interface{}{}
+				for _, node := range nodes {
+					nodeIds[node.FriendlyName] = node.ID
+				}
+
+				errs := util.MkLens(data)("node_ids", nodeIds)
+				errs = append(errs, util.MkLens(data)("concurrency", staticNodesDefaultConcurrency)...)
+				if len(errs) > 0 {
+					return nil, fmt.Errorf("failed to import static nodes %q", errs)
+				}
+				return []*schema.ResourceData{data}, nil
+			},
+		},
+
+		Schema:      staticNodesSchema,
+		Description: "Registers the nodes of a static node pool in bulk. When some nodes fail to be created, the apply fails and the others are kept in the state, tainted: untaint the resource so the next apply only creates the missing nodes. Importing it by node pool Id adopts all the nodes of the node pool.",
+	}
+}
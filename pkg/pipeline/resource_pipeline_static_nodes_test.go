@@ -0,0 +1,243 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	var running, maxRunning int32
+	var tasks []func() error
+	for i := 0; i < 20; i++ {
+		i := i
+		tasks = append(tasks, func() error {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+
+			if i%10 == 0 {
+				return fmt.Errorf("task %d failed", i)
+			}
+			return nil
+		})
+	}
+
+	err := runConcurrently(3, tasks)
+	if maxRunning > 3 {
+		t.Errorf("expected at most 3 tasks running at the same time, got %d", maxRunning)
+	}
+	if err == nil || !strings.Contains(err.Error(), "task 0 failed") || !strings.Contains(err.Error(), "task 10 failed") {
+		t.Errorf("expected the errors of tasks 0 and 10, got %v", err)
+	}
+}
+
+// fakeNodesServer serves the nodes API from memory, and fails to create the nodes named in failing
+type fakeNodesServer struct {
+	sync.Mutex
+	nodes   map[int]Node
+	nextId  int
+	created map[string]int
+	failing map[string]bool
+}
+
+func newFakeNodesServer(t *testing.T) (*fakeNodesServer, *resty.Client) {
+	fake := &fakeNodesServer{
+		nodes:   map[int]Node{},
+		nextId:  1,
+		created: map[string]int{},
+		failing: map[string]bool{},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := buildClient(server.URL, "test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, client.SetRetryCount(0)
+}
+
+func (f *fakeNodesServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"+nodesUrl+"/"))
+
+	switch r.Method {
+	case http.MethodGet:
+		nodes := []Node{}
+		for _, node := range f.nodes {
+			nodes = append(nodes, node)
+		}
+		_ = json.NewEncoder(w).Encode(nodes)
+	case http.MethodPost:
+		var node Node
+		_ = json.NewDecoder(r.Body).Decode(&node)
+		if f.failing[node.FriendlyName] {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		node.ID = f.nextId
+		node.SystemPropertyBag.Token = fmt.Sprintf("token-%d", node.ID)
+		f.nextId++
+		f.nodes[node.ID] = node
+		f.created[node.FriendlyName]++
+		_ = json.NewEncoder(w).Encode(node)
+	case http.MethodPut:
+		var node Node
+		_ = json.NewDecoder(r.Body).Decode(&node)
+		node.ID = id
+		node.SystemPropertyBag = f.nodes[id].SystemPropertyBag
+		f.nodes[id] = node
+	case http.MethodDelete:
+		delete(f.nodes, id)
+	}
+}
+
+func staticNodeBlocks(names ...string) []interface{} {
+	var blocks []interface{}
+	for i, name := range names {
+		blocks = append(blocks, map[string]interface{}{
+			"friendly_name":       name,
+			"ip_address":          fmt.Sprintf("10.0.1.%d", i+1),
+			"is_auto_initialized": true,
+			"is_swap_enabled":     false,
+		})
+	}
+	return blocks
+}
+
+func TestStaticNodes_partialFailure(t *testing.T) {
+	fake, client := newFakeNodesServer(t)
+	fake.failing["agent-02"] = true
+
+	staticNodesResource := pipelineStaticNodesResource()
+	data := staticNodesResource.TestResourceData()
+	for key, value := range map[string]interface{}{
+		"node_pool_id": 7,
+		"project_id":   1,
+		"concurrency":  2,
+		"node":         staticNodeBlocks("agent-01", "agent-02", "agent-03"),
+	} {
+		if err := data.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	diags := staticNodesResource.CreateContext(context.Background(), data, client)
+	if !diags.HasError() || len(diags) != 1 || !strings.Contains(diags[0].Detail, "agent-02") {
+		t.Fatalf("expected the partial failure to be reported as an error about agent-02, got %v", diags)
+	}
+	if data.Id() != "7" {
+		t.Errorf("expected the resource to be created with the node pool id, got '%s'", data.Id())
+	}
+	nodeIds := data.Get("node_ids").(map[string]interface{})
+	if _, ok := nodeIds["agent-02"]; ok || len(nodeIds) != 2 {
+		t.Errorf("expected only agent-01 and agent-03 in node_ids, got %v", nodeIds)
+	}
+	if nodes := data.Get("node").(*schema.Set); nodes.Len() != 2 {
+		t.Errorf("expected the failed node to be left out of the state, got %d nodes", nodes.Len())
+	}
+
+	// once untainted, the next apply only creates the missing node
+	fake.failing["agent-02"] = false
+	data = staticNodesResource.Data(data.State())
+	if err := data.Set("node", staticNodeBlocks("agent-01", "agent-02", "agent-03")); err != nil {
+		t.Fatal(err)
+	}
+	if diags := staticNodesResource.UpdateContext(context.Background(), data, client); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	for name, created := range fake.created {
+		if created != 1 {
+			t.Errorf("expected node %s to be created once, got %d", name, created)
+		}
+	}
+	if nodeIds := data.Get("node_ids").(map[string]interface{}); len(nodeIds) != 3 {
+		t.Errorf("expected 3 nodes, got %v", nodeIds)
+	}
+}
+
+func TestStaticNodes_resize(t *testing.T) {
+	fake, client := newFakeNodesServer(t)
+
+	staticNodesResource := pipelineStaticNodesResource()
+	data := staticNodesResource.TestResourceData()
+	for key, value := range map[string]interface{}{
+		"node_pool_id": 7,
+		"project_id":   1,
+		"concurrency":  5,
+		"node":         staticNodeBlocks("agent-01", "agent-02", "agent-03"),
+	} {
+		if err := data.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diags := staticNodesResource.CreateContext(context.Background(), data, client); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	agent01 := data.Get("node_ids").(map[string]interface{})["agent-01"]
+
+	// agent-02 and agent-03 are removed, agent-04 is added
+	data = staticNodesResource.Data(data.State())
+	if err := data.Set("node", staticNodeBlocks("agent-01", "agent-04")); err != nil {
+		t.Fatal(err)
+	}
+	if diags := staticNodesResource.UpdateContext(context.Background(), data, client); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	nodeIds := data.Get("node_ids").(map[string]interface{})
+	if len(nodeIds) != 2 || nodeIds["agent-01"] != agent01 || nodeIds["agent-04"] == nil {
+		t.Errorf("expected agent-01 to be kept and agent-04 to be added, got %v", nodeIds)
+	}
+	if len(fake.nodes) != 2 {
+		t.Errorf("expected 2 nodes left on the server, got %d", len(fake.nodes))
+	}
+	if fake.created["agent-01"] != 1 {
+		t.Errorf("expected agent-01 not to be recreated, got %d creations", fake.created["agent-01"])
+	}
+}
+
+func TestValidateUniqueStaticNodeNames(t *testing.T) {
+	node := func(name, ipAddress string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"friendly_name": cty.StringVal(name),
+			"ip_address":    cty.StringVal(ipAddress),
+		})
+	}
+
+	config := cty.ObjectVal(map[string]cty.Value{
+		"node": cty.SetVal([]cty.Value{node("agent-01", "10.0.1.1"), node("agent-02", "10.0.1.2")}),
+	})
+	if err := validateUniqueStaticNodeNames(config); err != nil {
+		t.Errorf("expected unique names to be valid, got %v", err)
+	}
+
+	config = cty.ObjectVal(map[string]cty.Value{
+		"node": cty.SetVal([]cty.Value{node("agent-01", "10.0.1.1"), node("agent-01", "10.0.1.2")}),
+	})
+	if err := validateUniqueStaticNodeNames(config); err == nil {
+		t.Error("expected an error for a duplicate friendly name")
+	}
+}
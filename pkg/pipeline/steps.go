@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"strconv"
+	"strings"
+)
+
+const stepsUrl = "pipelines/api/v1/steps"
+
+// Step is the execution of a pipeline step in a run
+type Step struct {
+	Name       string `json:"name"`
+	RunId      int    `json:"runId"`
+	StatusCode int    `json:"statusCode"`
+	StartedAt  string `json:"startedAt,omitempty"`
+	EndedAt    string `json:"endedAt,omitempty"`
+	ID         int    `json:"id"`
+}
+
+// Status codes of runs and steps
+const (
+	statusQueued     = 4000
+	statusProcessing = 4001
+	statusSuccess    = 4002
+	statusFailure    = 4003
+	statusError      = 4004
+	statusWaiting    = 4005
+	statusCancelled  = 4006
+	statusUnstable   = 4007
+	statusSkipped    = 4008
+	statusTimeout    = 4009
+	statusStopped    = 4010
+)
+
+var statusNames = map[int]string{
+	statusQueued:     "queued",
+	statusProcessing: "processing",
+	statusSuccess:    "success",
+	statusFailure:    "failure",
+	statusError:      "error",
+	statusWaiting:    "waiting",
+	statusCancelled:  "cancelled",
+	statusUnstable:   "unstable",
+	statusSkipped:    "skipped",
+	statusTimeout:    "timeout",
+	statusStopped:    "stopped",
+}
+
+// activeStepStatusCodes are the status codes of steps that hold or wait for a node
+var activeStepStatusCodes = joinIds([]int{statusQueued, statusProcessing, statusWaiting})
+
+// joinIds joins ids or status codes for the filters of the API
+func joinIds(ids []int) string {
+	joined := make([]string, len(ids))
+	for i, id := range ids {
+		joined[i] = strconv.Itoa(id)
+	}
+	return strings.Join(joined, ",")
+}
+
+// statusName returns the name of a status code, or the code itself if it is unknown
+func statusName(code int) string {
+	if name, ok := statusNames[code]; ok {
+		return name
+	}
+	return strconv.Itoa(code)
+}
+
+// isTerminalStatus returns whether a run or step with this status code is complete
+func isTerminalStatus(code int) bool {
+	switch code {
+	case statusSuccess, statusFailure, statusError, statusCancelled, statusUnstable, statusSkipped, statusTimeout, statusStopped:
+		return true
+	default:
+		return false
+	}
+}
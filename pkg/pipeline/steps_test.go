@@ -0,0 +1,18 @@
+package pipeline
+
+import (
+	"testing"
+)
+
+func TestIsTerminalStatus(t *testing.T) {
+	for _, code := range []int{statusQueued, statusProcessing, statusWaiting, 4999} {
+		if isTerminalStatus(code) {
+			t.Errorf("expected %s not to be terminal", statusName(code))
+		}
+	}
+	for _, code := range []int{statusSuccess, statusFailure, statusCancelled, statusTimeout} {
+		if !isTerminalStatus(code) {
+			t.Errorf("expected %s to be terminal", statusName(code))
+		}
+	}
+}
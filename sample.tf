@@ -163,7 +163,7 @@ resource "pipeline_source" "my-pipeline-source" {
 
 resource "pipeline_node_pool" "my-node-pool" {
   name                       = "my-node-pool"
-  project_id                 = data.pipeline_project.my-project.id
+  project_key                = project.myproject.key
   number_of_nodes            = 1
   is_on_demand               = true
   architecture               = "x86_64"